@@ -0,0 +1,102 @@
+package kong
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// AllowAbbreviations enables matching long flags and commands by any unambiguous prefix of their
+// name or aliases, eg. --verb for --verbose or inst for install.
+//
+// Exact matches always take precedence, and ambiguous prefixes are rejected with an error listing
+// the candidates. Hidden flags and commands must be typed in full.
+func AllowAbbreviations() Option {
+	return OptionFunc(func(k *Kong) error {
+		k.allowAbbreviations = true
+		return nil
+	})
+}
+
+// expandFlagAbbreviation returns the long flag name that "match" abbreviates, or "match" unchanged
+// if it is not an abbreviation of any flag.
+func expandFlagAbbreviation(flags []*Flag, match string, fold bool) (string, error) {
+	if !strings.HasPrefix(match, "--") {
+		return match, nil
+	}
+	owners := map[string]*Flag{}
+	for _, flag := range flags {
+		names := []string{"--" + flag.Name}
+		for _, alias := range flag.Aliases {
+			if utf8.RuneCountInString(alias) > 1 {
+				names = append(names, "--"+alias)
+			}
+		}
+		if neg := negatableFlagName(flag.Name, flag.Tag.Negatable); neg != "" {
+			names = append(names, neg)
+		}
+		for _, name := range names {
+			if name == match || (fold && strings.EqualFold(name, match)) {
+				return match, nil
+			}
+			if !flag.Hidden && hasPrefix(name, match, fold) {
+				owners[name] = flag
+			}
+		}
+	}
+	return expandAbbreviation("flag", match, owners)
+}
+
+// expandCommandAbbreviation returns the name of the command whose name or aliases "match"
+// abbreviates, or "match" unchanged if it is not an abbreviation of any command under node.
+func expandCommandAbbreviation(node *Node, match string, fold bool) (string, error) {
+	owners := map[string]*Node{}
+	for _, branch := range node.Children {
+		if branch.Type != CommandNode {
+			continue
+		}
+		for _, name := range append([]string{branch.Name}, branch.Aliases...) {
+			if name == match || (fold && strings.EqualFold(name, match)) {
+				return match, nil
+			}
+			if !branch.Hidden && hasPrefix(name, match, fold) {
+				owners[name] = branch
+			}
+		}
+	}
+	name, err := expandAbbreviation("command", match, owners)
+	if err != nil || owners[name] == nil {
+		return name, err
+	}
+	return owners[name].Name, nil
+}
+
+// hasPrefix returns true if s starts with prefix, ignoring case if fold is true.
+func hasPrefix(s, prefix string, fold bool) bool {
+	if fold {
+		return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+	}
+	return strings.HasPrefix(s, prefix)
+}
+
+// expandAbbreviation returns the single name in "owners" if they all belong to the same owner.
+func expandAbbreviation[T comparable](kind, match string, owners map[string]T) (string, error) {
+	if len(owners) == 0 {
+		return match, nil
+	}
+	names := make([]string, 0, len(owners))
+	for name := range owners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names[1:] {
+		if owners[name] != owners[names[0]] {
+			for i, name := range names {
+				names[i] = fmt.Sprintf("%q", name)
+			}
+			return "", fmt.Errorf("ambiguous %s %s, could be one of %s", kind, match, strings.Join(names, ", "))
+		}
+	}
+	return names[0], nil
+}
@@ -0,0 +1,317 @@
+package kong
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AliasProvider may be implemented by a Resolver to provide user-defined command aliases from the
+// configuration key named by the AliasesFrom option, in the same form as the Aliases option.
+//
+// The JSON resolver provides aliases from an object, eg. {"aliases": {"co": "checkout"}}.
+type AliasProvider interface {
+	Aliases(key string) (map[string]string, error)
+}
+
+// Aliases adds user-defined command aliases, eg. {"co": "checkout --quiet"}.
+//
+// An alias is expanded when it is given in place of a command, after any flags of the application
+// and before "--". "$1", "$2", etc. in the
+// expansion are replaced by the arguments following the alias, and "$@" by all of them. If the
+// expansion has no placeholders the arguments are appended to it. Aliases may refer to other
+// aliases, but may not be recursive or have the same name as a command.
+//
+// Aliases are listed in help under the "Aliases" group.
+func Aliases(aliases map[string]string) Option {
+	return OptionFunc(func(k *Kong) error {
+		if k.aliases == nil {
+			k.aliases = map[string]string{}
+		}
+		for name, expansion := range aliases {
+			k.aliases[name] = expansion
+		}
+		return nil
+	})
+}
+
+// AliasesFrom reads aliases from key of resolvers implementing AliasProvider, such as those
+// loaded by Configuration, eg. with a key of "aliases" the JSON {"aliases": {"co": "checkout"}}.
+//
+// Aliases given by the Aliases and AliasFile options take precedence.
+func AliasesFrom(key string) Option {
+	return OptionFunc(func(k *Kong) error {
+		k.aliasesKey = key
+		return nil
+	})
+}
+
+// AliasFile loads aliases from files containing lines in the form "<alias> = <expansion>". Blank
+// lines and lines starting with "#" are ignored, as are missing files.
+func AliasFile(paths ...string) Option {
+	return OptionFunc(func(k *Kong) error {
+		for _, path := range paths {
+			f, err := os.Open(ExpandPath(path))
+			if os.IsNotExist(err) {
+				continue
+			} else if err != nil {
+				return err
+			}
+			aliases, err := parseAliases(f)
+			_ = f.Close()
+			if err != nil {
+				return fmt.Errorf("%s:%w", path, err)
+			}
+			if err := Aliases(aliases).Apply(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func parseAliases(r io.Reader) (map[string]string, error) {
+	aliases := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, expansion, ok := strings.Cut(line, "=")
+		name, expansion = strings.TrimSpace(name), strings.TrimSpace(expansion)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("%d: expected \"<alias> = <expansion>\" but got %q", n, line)
+		}
+		aliases[name] = expansion
+	}
+	return aliases, scanner.Err()
+}
+
+// buildAliases collects aliases from resolvers if AliasesFrom was given, ensures they do not
+// shadow commands, and adds them to the model for help.
+func (k *Kong) buildAliases() error {
+	for _, resolver := range k.resolvers {
+		provider, ok := resolver.(AliasProvider)
+		if !ok || k.aliasesKey == "" {
+			continue
+		}
+		aliases, err := provider.Aliases(k.aliasesKey)
+		if err != nil {
+			return err
+		}
+		// Explicit aliases take precedence over those from configuration.
+		for name, expansion := range aliases {
+			if _, ok := k.aliases[name]; !ok {
+				if k.aliases == nil {
+					k.aliases = map[string]string{}
+				}
+				k.aliases[name] = expansion
+			}
+		}
+	}
+	if len(k.aliases) == 0 {
+		return nil
+	}
+	builtin := map[string]bool{}
+	for _, child := range k.Model.Children {
+		builtin[k.aliasKey(child.Name)] = true
+		for _, alias := range child.Aliases {
+			builtin[k.aliasKey(alias)] = true
+		}
+	}
+	names := make([]string, 0, len(k.aliases))
+	for name := range k.aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	seen := map[string]string{}
+	for _, name := range names {
+		if name == "" || strings.HasPrefix(name, "-") {
+			return fmt.Errorf("invalid alias name %q", name)
+		}
+		if builtin[k.aliasKey(name)] {
+			return fmt.Errorf("alias %q shadows a command of the same name", name)
+		}
+		if prev, ok := seen[k.aliasKey(name)]; ok {
+			return fmt.Errorf("aliases %q and %q differ only in case", prev, name)
+		}
+		seen[k.aliasKey(name)] = name
+		if len(k.Model.Positional) > 0 {
+			return fmt.Errorf("alias %q can't be mixed with positional arguments", name)
+		}
+		// Escape "$" so the expansion is not interpolated.
+		help := "Alias for \"" + strings.ReplaceAll(k.aliases[name], "$", "$$") + "\"."
+		cmd := NewCommand(name).Help(help).Group("Aliases")
+		tag, err := cmd.tag()
+		if err != nil {
+			return err
+		}
+		child, err := buildCommand(k, cmd, CommandNode, map[string]bool{})
+		if err != nil {
+			return err
+		}
+		if err := attachChild(k, k.Model.Node, child, k.Model.Target, reflect.StructField{Name: name}, tag, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (k *Kong) aliasKey(name string) string {
+	if k.caseInsensitive {
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+// lookupAlias returns the expansion of the alias name, ignoring case if the parser does.
+func (k *Kong) lookupAlias(name string) (string, bool) {
+	if expansion, ok := k.aliases[name]; ok {
+		return expansion, true
+	}
+	if !k.caseInsensitive {
+		return "", false
+	}
+	for alias, expansion := range k.aliases {
+		if strings.EqualFold(alias, name) {
+			return expansion, true
+		}
+	}
+	return "", false
+}
+
+// isAliasCommand returns true if branch, a child of node, is the command listing an alias in help,
+// which is never selected itself.
+func (c *Context) isAliasCommand(node, branch *Node) bool {
+	_, ok := c.aliases[branch.Name]
+	return node == c.Model.Node && ok
+}
+
+var aliasPlaceholderRegex = regexp.MustCompile(`\$(\d+)`)
+
+// expandAlias replaces the next argument, if it is an alias, and the arguments following it with
+// the expansion of the alias. Returns false if the argument is not an alias.
+//
+// It is called by trace in place of matching a command, so flags before the alias and their values
+// have already been parsed.
+func (c *Context) expandAlias() (bool, error) {
+	if _, ok := c.lookupAlias(c.scan.Peek().String()); !ok {
+		return false, nil
+	}
+	args := []string{}
+	for _, token := range c.scan.PopWhile(func(token Token) bool { return !token.IsEOL() }) {
+		args = append(args, token.String())
+	}
+	args, err := c.expandAliases(args)
+	if err != nil {
+		return false, err
+	}
+	// Note: tokens must be pushed in reverse order.
+	for i := len(args) - 1; i >= 0; i-- {
+		c.scan.Push(args[i])
+	}
+	return true, nil
+}
+
+// expandAliases expands the first of args while it is an alias.
+func (k *Kong) expandAliases(args []string) ([]string, error) {
+	seen := []string{}
+	for len(args) > 0 {
+		name := args[0]
+		expansion, ok := k.lookupAlias(name)
+		if !ok {
+			break
+		}
+		for _, prev := range seen {
+			if k.aliasKey(prev) == k.aliasKey(name) {
+				return nil, fmt.Errorf("alias cycle %s -> %s", strings.Join(seen, " -> "), name)
+			}
+		}
+		seen = append(seen, name)
+		words, err := splitAlias(expansion)
+		if err != nil {
+			return nil, fmt.Errorf("alias %q: %w", name, err)
+		}
+		if len(words) == 0 {
+			return nil, fmt.Errorf("alias %q is empty", name)
+		}
+		if args, err = substituteAlias(name, words, args[1:]); err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+// substituteAlias replaces placeholders in words with args, appending args if there are none.
+func substituteAlias(name string, words, args []string) ([]string, error) {
+	out := []string{}
+	placeholders := false
+	for _, word := range words {
+		if word == "$@" {
+			out = append(out, args...)
+			placeholders = true
+			continue
+		}
+		var err error
+		word = aliasPlaceholderRegex.ReplaceAllStringFunc(word, func(placeholder string) string {
+			placeholders = true
+			n, _ := strconv.Atoi(placeholder[1:])
+			if n < 1 || n > len(args) {
+				err = fmt.Errorf("alias %q requires argument %s", name, placeholder)
+				return ""
+			}
+			return args[n-1]
+		})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, word)
+	}
+	if !placeholders {
+		out = append(out, args...)
+	}
+	return out, nil
+}
+
+// splitAlias splits an alias expansion into words on whitespace, respecting single and double
+// quotes.
+func splitAlias(s string) ([]string, error) {
+	words := []string{}
+	word := strings.Builder{}
+	inWord := false
+	var quote rune
+	for _, r := range s {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			word.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in %q", s)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,132 @@
+package kong_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/alecthomas/kong"
+)
+
+type aliasCLI struct {
+	Debug    bool   `short:"d"`
+	Config   string `short:"c"`
+	Checkout struct {
+		Quiet  bool     `short:"q"`
+		Branch string   `arg:""`
+		Paths  []string `arg:"" optional:""`
+	} `cmd:"" help:"Check out a branch."`
+	Commit struct {
+		Message string `short:"m"`
+	} `cmd:""`
+}
+
+func TestAliases(t *testing.T) {
+	var cli aliasCLI
+	w := &strings.Builder{}
+	p := mustNew(t, &cli, kong.Writers(w, w), kong.Exit(func(int) {}), kong.Aliases(map[string]string{
+		"co":  "checkout --quiet",
+		"cob": "co $1 -- $@",
+		"cm":  `commit -m "$1 [skip ci]"`,
+	}))
+
+	ctx, err := p.Parse([]string{"-d", "co", "main", "a"})
+	assert.NoError(t, err)
+	assert.Equal(t, "checkout <branch> <paths>", ctx.Command())
+	assert.True(t, cli.Debug)
+	assert.True(t, cli.Checkout.Quiet)
+	assert.Equal(t, "main", cli.Checkout.Branch)
+	assert.Equal(t, []string{"a"}, cli.Checkout.Paths)
+
+	_, err = p.Parse([]string{"cob", "dev", "x"})
+	assert.NoError(t, err)
+	assert.Equal(t, "dev", cli.Checkout.Branch)
+	assert.Equal(t, []string{"dev", "x"}, cli.Checkout.Paths)
+
+	_, err = p.Parse([]string{"cm", "fix typo"})
+	assert.NoError(t, err)
+	assert.Equal(t, "fix typo [skip ci]", cli.Commit.Message)
+
+	_, err = p.Parse([]string{"cm"})
+	assert.EqualError(t, err, `alias "cm" requires argument $1`)
+
+	// Flag values are not aliases.
+	ctx, err = p.Parse([]string{"--config", "cm", "-c", "co", "co", "dev"})
+	assert.NoError(t, err)
+	assert.Equal(t, "checkout <branch>", ctx.Command())
+	assert.Equal(t, "co", cli.Config)
+	assert.Equal(t, "dev", cli.Checkout.Branch)
+
+	// Nor are arguments after "--".
+	_, err = p.Parse([]string{"--", "co"})
+	assert.EqualError(t, err, `unexpected argument co, did you mean one of "cm", "cob", "commit"?`)
+
+	_, _ = p.Parse([]string{"--help"})
+	assert.Contains(t, w.String(), "Aliases\n  cm [flags]\n    Alias for \"commit -m \"$1 [skip ci]\"\".")
+	assert.Contains(t, w.String(), "  co [flags]\n    Alias for \"checkout --quiet\".")
+}
+
+func TestAliasErrors(t *testing.T) {
+	var cli aliasCLI
+	p := mustNew(t, &cli, kong.Aliases(map[string]string{"a": "b", "b": "c x", "c": "a"}))
+	_, err := p.Parse([]string{"a"})
+	assert.EqualError(t, err, "alias cycle a -> b -> c -> a")
+
+	_, err = kong.New(&cli, kong.Aliases(map[string]string{"commit": "checkout"}))
+	assert.EqualError(t, err, `alias "commit" shadows a command of the same name`)
+
+	_, err = kong.New(&cli, kong.CaseInsensitive(), kong.Aliases(map[string]string{"co": "checkout", "CO": "commit"}))
+	assert.EqualError(t, err, `aliases "CO" and "co" differ only in case`)
+}
+
+func TestAliasCaseInsensitive(t *testing.T) {
+	var cli aliasCLI
+	p := mustNew(t, &cli, kong.CaseInsensitive(), kong.Aliases(map[string]string{"co": "checkout --quiet"}))
+	ctx, err := p.Parse([]string{"CO", "main"})
+	assert.NoError(t, err)
+	assert.Equal(t, "checkout <branch>", ctx.Command())
+	assert.True(t, cli.Checkout.Quiet)
+}
+
+func TestAliasSources(t *testing.T) {
+	dir := t.TempDir()
+	aliasFile := filepath.Join(dir, "aliases")
+	err := os.WriteFile(aliasFile, []byte("# Aliases\nco = checkout\n\nci = commit -m wip\n"), 0o600)
+	assert.NoError(t, err)
+	configFile := filepath.Join(dir, "config.json")
+	err = os.WriteFile(configFile, []byte(`{"debug": true, "aliases": {"ci": "ignored", "cq": "checkout -q"}}`), 0o600)
+	assert.NoError(t, err)
+
+	var cli aliasCLI
+	p := mustNew(t, &cli, kong.AliasFile(aliasFile, filepath.Join(dir, "missing")), kong.Configuration(kong.StrictJSON, configFile), kong.AliasesFrom("aliases"))
+	_, err = p.Parse([]string{"ci"})
+	assert.NoError(t, err)
+	assert.Equal(t, "wip", cli.Commit.Message)
+	assert.True(t, cli.Debug)
+	_, err = p.Parse([]string{"cq", "main"})
+	assert.NoError(t, err)
+	assert.True(t, cli.Checkout.Quiet)
+
+	// Configuration is only read for aliases with AliasesFrom.
+	var config struct {
+		Aliases map[string]int
+		Command string `arg:"" optional:""`
+	}
+	err = os.WriteFile(configFile, []byte(`{"aliases": {"a": 1}}`), 0o600)
+	assert.NoError(t, err)
+	_, err = mustNew(t, &config, kong.Configuration(kong.JSON, configFile)).Parse(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 1}, config.Aliases)
+
+	err = os.WriteFile(aliasFile, []byte("co checkout\n"), 0o600)
+	assert.NoError(t, err)
+	_, err = kong.New(&cli, kong.AliasFile(aliasFile))
+	assert.EqualError(t, err, aliasFile+`:1: expected "<alias> = <expansion>" but got "co checkout"`)
+
+	// Only missing files are ignored.
+	_, err = kong.New(&cli, kong.AliasFile(filepath.Join(aliasFile, "aliases")))
+	assert.Error(t, err)
+}
@@ -2,6 +2,7 @@ package kong
 
 import (
 	"fmt"
+	"reflect"
 	"strconv"
 	"testing"
 
@@ -77,3 +78,50 @@ func Benchmark_interpolateValue(b *testing.B) {
 	}
 	b.ReportAllocs()
 }
+
+// largeGrammar returns the type of a grammar with 300 commands of 10 flags each.
+func largeGrammar() reflect.Type {
+	flags := make([]reflect.StructField, 10)
+	for i := range flags {
+		flags[i] = reflect.StructField{
+			Name: fmt.Sprintf("Flag%d", i),
+			Type: reflect.TypeOf(""),
+			Tag:  reflect.StructTag(fmt.Sprintf(`help:"Flag %d." default:"${default_value}"`, i)),
+		}
+	}
+	cmdType := reflect.StructOf(flags)
+	cmds := make([]reflect.StructField, 300)
+	for i := range cmds {
+		cmds[i] = reflect.StructField{
+			Name: fmt.Sprintf("Command%d", i),
+			Type: cmdType,
+			Tag:  reflect.StructTag(fmt.Sprintf(`cmd:"" help:"Command %d."`, i)),
+		}
+	}
+	return reflect.StructOf(cmds)
+}
+
+func benchmarkLargeGrammar(b *testing.B, options ...Option) {
+	b.Helper()
+	grammarType := largeGrammar()
+	options = append(options, Vars{"default_value": "value"}, Exit(func(int) {}))
+	for i := 0; i < b.N; i++ {
+		k, err := New(reflect.New(grammarType).Interface(), options...)
+		assert.NoError(b, err)
+		_, err = k.Parse([]string{"command-150", "--flag-5=x"})
+		assert.NoError(b, err)
+	}
+	b.ReportAllocs()
+}
+
+func BenchmarkKong_LazyCommands(b *testing.B) {
+	b.Run("lazy=false", func(b *testing.B) { benchmarkLargeGrammar(b) })
+	b.Run("lazy=true", func(b *testing.B) { benchmarkLargeGrammar(b, LazyCommands()) })
+}
+
+func BenchmarkKong_ModelCache(b *testing.B) {
+	cache, err := NewModelCache(reflect.New(largeGrammar()).Interface(), Vars{"default_value": "value"})
+	assert.NoError(b, err)
+	b.Run("cached=false", func(b *testing.B) { benchmarkLargeGrammar(b) })
+	b.Run("cached=true", func(b *testing.B) { benchmarkLargeGrammar(b, WithModelCache(cache)) })
+}
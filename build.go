@@ -15,7 +15,8 @@ type Plugins []any
 func build(k *Kong, ast any) (app *Application, err error) {
 	v := reflect.ValueOf(ast)
 	iv := reflect.Indirect(v)
-	if v.Kind() != reflect.Ptr || iv.Kind() != reflect.Struct {
+	builder, isBuilder := ast.(*CommandBuilder)
+	if !isBuilder && (v.Kind() != reflect.Ptr || iv.Kind() != reflect.Struct) {
 		return nil, fmt.Errorf("expected a pointer to a struct but got %T", ast)
 	}
 
@@ -26,7 +27,12 @@ func build(k *Kong, ast any) (app *Application, err error) {
 		seenFlags[flag.Name] = true
 	}
 
-	node, err := buildNode(k, iv, ApplicationNode, newEmptyTag(), seenFlags)
+	var node *Node
+	if isBuilder {
+		node, err = buildCommand(k, builder, ApplicationNode, seenFlags)
+	} else {
+		node, err = buildNode(k, iv, ApplicationNode, newEmptyTag(), seenFlags)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -53,7 +59,7 @@ type flattenedField struct {
 	tag   *Tag
 }
 
-func flattenedFields(v reflect.Value, ptag *Tag) (out []flattenedField, err error) {
+func flattenedFields(k *Kong, v reflect.Value, ptag *Tag) (out []flattenedField, err error) {
 	v = reflect.Indirect(v)
 	if v.Kind() != reflect.Struct {
 		return out, nil
@@ -62,7 +68,7 @@ func flattenedFields(v reflect.Value, ptag *Tag) (out []flattenedField, err erro
 	for i := 0; i < v.NumField(); i++ {
 		ft := v.Type().Field(i)
 		fv := v.Field(i)
-		tag, err := parseTag(v, ft)
+		tag, err := k.parseFieldTag(v, ft)
 		if err != nil {
 			return nil, err
 		}
@@ -98,7 +104,7 @@ func flattenedFields(v reflect.Value, ptag *Tag) (out []flattenedField, err erro
 			fv = fv.Elem()
 		} else if fv.Type() == reflect.TypeOf(Plugins{}) {
 			for i := 0; i < fv.Len(); i++ {
-				fields, ferr := flattenedFields(fv.Index(i).Elem(), tag)
+				fields, ferr := flattenedFields(k, fv.Index(i).Elem(), tag)
 				if ferr != nil {
 					return nil, ferr
 				}
@@ -106,7 +112,7 @@ func flattenedFields(v reflect.Value, ptag *Tag) (out []flattenedField, err erro
 			}
 			continue
 		}
-		sub, err := flattenedFields(fv, tag)
+		sub, err := flattenedFields(k, fv, tag)
 		if err != nil {
 			return nil, err
 		}
@@ -142,7 +148,7 @@ func buildNode(k *Kong, v reflect.Value, typ NodeType, tag *Tag, seenFlags map[s
 		Target: v,
 		Tag:    tag,
 	}
-	fields, err := flattenedFields(v, tag)
+	fields, err := flattenedFields(k, v, tag)
 	if err != nil {
 		return nil, err
 	}
@@ -199,34 +205,27 @@ MAIN:
 		}
 	}
 
-	// Validate if there are no duplicate names
-	if err := checkDuplicateNames(node, v); err != nil {
+	if err := finishNode(k, node, v, seenFlags); err != nil {
 		return nil, err
 	}
+	return node, nil
+}
+
+// finishNode validates a built node and "unsees" its flags so they may be reused by siblings.
+func finishNode(k *Kong, node *Node, v reflect.Value, seenFlags map[string]bool) error {
+	// Validate if there are no duplicate names
+	if err := checkDuplicateNames(node, v, k.caseInsensitive); err != nil {
+		return err
+	}
 
 	// "Unsee" flags.
 	for _, flag := range node.Flags {
-		delete(seenFlags, "--"+flag.Name)
-		if flag.Short != 0 {
-			delete(seenFlags, "-"+string(flag.Short))
-		}
-		if negFlag := negatableFlagName(flag.Name, flag.Tag.Negatable); negFlag != "" {
-			delete(seenFlags, negFlag)
-		}
-		for _, aflag := range flag.Aliases {
-			if utf8.RuneCountInString(aflag) == 1 {
-				delete(seenFlags, "-"+aflag)
-			} else {
-				delete(seenFlags, "--"+aflag)
-			}
+		for _, key := range k.flagKeys(flag) {
+			delete(seenFlags, key)
 		}
 	}
 
-	if err := validatePositionalArguments(node); err != nil {
-		return nil, err
-	}
-
-	return node, nil
+	return validatePositionalArguments(node)
 }
 
 func validatePositionalArguments(node *Node) error {
@@ -252,10 +251,21 @@ func validatePositionalArguments(node *Node) error {
 }
 
 func buildChild(k *Kong, node *Node, typ NodeType, v reflect.Value, ft reflect.StructField, fv reflect.Value, tag *Tag, name string, seenFlags map[string]bool) error {
+	if k.lazyCommands && typ == CommandNode && !tag.HasDefault && !tag.Passthrough {
+		return buildLazyChild(k, node, v, ft, fv, tag, name)
+	}
 	child, err := buildNode(k, fv, typ, newEmptyTag(), seenFlags)
 	if err != nil {
 		return err
 	}
+	if provider, ok := fv.Addr().Interface().(HelpProvider); ok {
+		child.Detail = provider.Help()
+	}
+	return attachChild(k, node, child, v, ft, tag, name)
+}
+
+// attachChild adds a built child command or branching argument to node.
+func attachChild(k *Kong, node *Node, child *Node, v reflect.Value, ft reflect.StructField, tag *Tag, name string) error {
 	child.Name = name
 	child.Tag = tag
 	child.Parent = node
@@ -264,10 +274,6 @@ func buildChild(k *Kong, node *Node, typ NodeType, v reflect.Value, ft reflect.S
 	child.Group = buildGroupForKey(k, tag.Group)
 	child.Aliases = tag.Aliases
 
-	if provider, ok := fv.Addr().Interface().(HelpProvider); ok {
-		child.Detail = provider.Help()
-	}
-
 	// A branching argument. This is a bit hairy, as we let buildNode() do the parsing, then check that
 	// a positional argument is provided to the child, and move it to the branching argument field.
 	if tag.Arg {
@@ -320,6 +326,30 @@ func buildField(k *Kong, node *Node, v reflect.Value, ft reflect.StructField, fv
 	if mapper == nil {
 		return failField(v, ft, "unsupported field type %s, perhaps missing a cmd:\"\" tag?", ft.Type)
 	}
+	if mapper, ok := mapper.(structMapper); ok {
+		fields, err := mapper.fields(fv.Type())
+		if err != nil {
+			return failField(v, ft, "%s", err)
+		}
+		if len(fields) == 0 {
+			return failField(v, ft, "struct %s has no fields", ft.Type)
+		}
+	}
+
+	enum := tag.Enum
+	if typ, values, ok := enumElemValues(fv.Type()); ok {
+		if err := checkEnumValues(typ, values); err != nil {
+			return failField(v, ft, "%s", err)
+		}
+		if enum == "" {
+			enum = strings.Join(enumNames(values), ",")
+		}
+		// As with the enum tag, an unset value must still be valid.
+		required := (!tag.Arg && tag.Required) || (tag.Arg && !tag.Optional)
+		if _, zero := enumName(values, reflect.Zero(typ)); typ == fv.Type() && !zero && !required && !tag.HasDefault {
+			return failField(v, ft, "enum %s is only valid if it is either required, has a valid default value or has the zero value as one of its values", typ)
+		}
+	}
 
 	value := &Value{
 		Name:            name,
@@ -331,7 +361,7 @@ func buildField(k *Kong, node *Node, v reflect.Value, ft reflect.StructField, fv
 		Mapper:          mapper,
 		Tag:             tag,
 		Target:          fv,
-		Enum:            tag.Enum,
+		Enum:            enum,
 		Passthrough:     tag.Passthrough,
 		PassthroughMode: tag.PassthroughMode,
 
@@ -343,19 +373,22 @@ func buildField(k *Kong, node *Node, v reflect.Value, ft reflect.StructField, fv
 	if tag.Arg {
 		node.Positional = append(node.Positional, value)
 	} else {
-		if seenFlags["--"+value.Name] {
+		if tag.Global && node.Type != ApplicationNode {
+			return failField(v, ft, "global flag --%s must be declared on the application", value.Name)
+		}
+		if seenFlags[k.flagKey("--"+value.Name)] {
 			return failField(v, ft, "duplicate flag --%s", value.Name)
 		}
-		seenFlags["--"+value.Name] = true
+		seenFlags[k.flagKey("--"+value.Name)] = true
 		for _, alias := range tag.Aliases {
 			aliasFlag := "--" + alias
 			if utf8.RuneCountInString(alias) == 1 {
 				aliasFlag = "-" + alias
 			}
-			if seenFlags[aliasFlag] {
+			if seenFlags[k.flagKey(aliasFlag)] {
 				return failField(v, ft, "duplicate flag %s", aliasFlag)
 			}
-			seenFlags[aliasFlag] = true
+			seenFlags[k.flagKey(aliasFlag)] = true
 		}
 		if tag.Short != 0 {
 			if seenFlags["-"+string(tag.Short)] {
@@ -365,10 +398,10 @@ func buildField(k *Kong, node *Node, v reflect.Value, ft reflect.StructField, fv
 		}
 		if tag.Negatable != "" {
 			negFlag := negatableFlagName(value.Name, tag.Negatable)
-			if seenFlags[negFlag] {
+			if seenFlags[k.flagKey(negFlag)] {
 				return failField(v, ft, "duplicate negation flag %s", negFlag)
 			}
-			seenFlags[negFlag] = true
+			seenFlags[k.flagKey(negFlag)] = true
 		}
 		flag := &Flag{
 			Value:       value,
@@ -404,19 +437,28 @@ func buildGroupForKey(k *Kong, key string) *Group {
 	}
 }
 
-func checkDuplicateNames(node *Node, v reflect.Value) error {
+func checkDuplicateNames(node *Node, v reflect.Value, fold bool) error {
 	seenNames := make(map[string]struct{})
 	for _, node := range node.Children {
-		if _, ok := seenNames[node.Name]; ok {
-			name := v.Type().Name()
-			if name == "" {
-				name = "<anonymous struct>"
-			}
-			return fmt.Errorf("duplicate command name %q in command %q", node.Name, name)
+		key := node.Name
+		if fold {
+			key = strings.ToLower(key)
+		}
+		if _, ok := seenNames[key]; ok {
+			return fmt.Errorf("duplicate command name %q in command %q", node.Name, structName(v))
 		}
 
-		seenNames[node.Name] = struct{}{}
+		seenNames[key] = struct{}{}
 	}
 
 	return nil
 }
+
+// flagKey returns the key used to detect duplicate flags, folding the case of long flags
+// if the CaseInsensitive() option is enabled.
+func (k *Kong) flagKey(flag string) string {
+	if k.caseInsensitive && strings.HasPrefix(flag, "--") {
+		return strings.ToLower(flag)
+	}
+	return flag
+}
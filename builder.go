@@ -0,0 +1,226 @@
+package kong
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Ref refers to a variable populated by a flag or argument of a CommandBuilder.
+type Ref struct {
+	value reflect.Value
+}
+
+// Var returns a Ref to a variable of any type Kong can decode.
+func Var[T any](target *T) Ref {
+	return Ref{reflect.ValueOf(target).Elem()}
+}
+
+// A CommandBuilder constructs a command programmatically rather than from a struct, eg.
+//
+//	var region string
+//	deploy := kong.NewCommand("deploy").
+//		Help("Deploy the application.").
+//		Flag("region", kong.Var(&region), `help:"Region to deploy to."`, `required:""`).
+//		Run(func(ctx *kong.Context) error { ... })
+//
+// Flags, arguments and commands accept the same tags as struct fields, in the form
+// <key>:"<value>". The resulting model is identical to that of an equivalent struct.
+//
+// A CommandBuilder may be passed to New as the grammar, added to a struct grammar with the
+// Commands or DynamicCommand options, and may itself contain struct commands.
+type CommandBuilder struct {
+	name     string
+	help     string
+	detail   string
+	group    string
+	tags     []string
+	fields   []builderField
+	children []builderChild
+	run      reflect.Value
+	err      error
+}
+
+type builderField struct {
+	name   string
+	target reflect.Value
+	tags   []string
+}
+
+// A child of a CommandBuilder, either another builder or a struct.
+type builderChild struct {
+	builder *CommandBuilder
+	name    string
+	help    string
+	cmd     any
+	tags    []string
+}
+
+// builtCommand is the Target of nodes built from a CommandBuilder.
+type builtCommand struct {
+	name string
+	run  reflect.Value
+}
+
+// NewCommand starts building a command called name.
+//
+// The name of a CommandBuilder passed to New is ignored, use the Name option instead.
+func NewCommand(name string) *CommandBuilder {
+	return &CommandBuilder{name: name}
+}
+
+// Help sets the short help of the command.
+func (c *CommandBuilder) Help(help string) *CommandBuilder {
+	c.help = help
+	return c
+}
+
+// Detail sets the detailed help of the command.
+func (c *CommandBuilder) Detail(detail string) *CommandBuilder {
+	c.detail = detail
+	return c
+}
+
+// Group sets the help group of the command.
+func (c *CommandBuilder) Group(group string) *CommandBuilder {
+	c.group = group
+	return c
+}
+
+// Tags adds tags to the command, eg. `aliases:"d"` or `default:"1"`.
+func (c *CommandBuilder) Tags(tags ...string) *CommandBuilder {
+	c.tags = append(c.tags, tags...)
+	return c
+}
+
+// Flag adds a flag populating target.
+func (c *CommandBuilder) Flag(name string, target Ref, tags ...string) *CommandBuilder {
+	return c.field(name, target, tags)
+}
+
+// Arg adds a positional argument populating target.
+func (c *CommandBuilder) Arg(name string, target Ref, tags ...string) *CommandBuilder {
+	return c.field(name, target, append([]string{`arg:""`}, tags...))
+}
+
+func (c *CommandBuilder) field(name string, target Ref, tags []string) *CommandBuilder {
+	if !target.value.IsValid() {
+		c.fail(fmt.Errorf("%s: invalid target", name))
+		return c
+	}
+	c.fields = append(c.fields, builderField{name: name, target: target.value, tags: tags})
+	return c
+}
+
+// Command adds a sub-command built by another CommandBuilder.
+func (c *CommandBuilder) Command(cmd *CommandBuilder) *CommandBuilder {
+	c.children = append(c.children, builderChild{builder: cmd})
+	return c
+}
+
+// Struct adds a sub-command built from a pointer to a struct, as with DynamicCommand.
+func (c *CommandBuilder) Struct(name, help string, cmd any, tags ...string) *CommandBuilder {
+	if v := reflect.ValueOf(cmd); v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		c.fail(fmt.Errorf("%s: expected a pointer to a struct but got %T", name, cmd))
+		return c
+	}
+	c.children = append(c.children, builderChild{name: name, help: help, cmd: cmd, tags: tags})
+	return c
+}
+
+// Run sets the function called by Context.Run when the command is selected.
+//
+// Its parameters are bound in the same way as those of a Run() method, and it must return an
+// error.
+func (c *CommandBuilder) Run(fn any) *CommandBuilder {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.Type().NumOut() != 1 || !v.Type().Out(0).Implements(callbackReturnSignature) {
+		c.fail(fmt.Errorf("%s: expected a Run function returning an error but got %T", c.name, fn))
+		return c
+	}
+	c.run = v
+	return c
+}
+
+func (c *CommandBuilder) fail(err error) {
+	c.err = errors.Join(c.err, err)
+}
+
+// tag returns the tag of the command node.
+func (c *CommandBuilder) tag() (*Tag, error) {
+	tag, err := parseTagString(strings.Join(append([]string{`cmd:""`}, c.tags...), " "))
+	if err != nil {
+		return nil, err
+	}
+	tag.Name = c.name
+	tag.Help = c.help
+	tag.Group = c.group
+	tag.Cmd = true
+	return tag, nil
+}
+
+// buildCommand builds a Node from a CommandBuilder.
+func buildCommand(k *Kong, c *CommandBuilder, typ NodeType, seenFlags map[string]bool) (*Node, error) {
+	if c.err != nil {
+		return nil, fmt.Errorf("%s: %w", c.name, c.err)
+	}
+	v := reflect.ValueOf(&builtCommand{name: c.name, run: c.run}).Elem()
+	node := &Node{
+		Type:   typ,
+		Target: v,
+		Tag:    newEmptyTag(),
+		Detail: c.detail,
+	}
+	for _, field := range c.fields {
+		ft := reflect.StructField{
+			Name: field.name,
+			Type: field.target.Type(),
+			Tag:  reflect.StructTag(strings.Join(field.tags, " ")),
+		}
+		tag, err := k.parseFieldTag(v, ft)
+		if err != nil {
+			return nil, err
+		}
+		name := tag.Name
+		if name == "" {
+			name = field.name
+		}
+		if err := buildField(k, node, v, ft, field.target, tag, name, seenFlags); err != nil {
+			return nil, err
+		}
+	}
+	for _, child := range c.children {
+		if child.builder == nil {
+			tag, err := parseTagString(strings.Join(child.tags, " "))
+			if err != nil {
+				return nil, err
+			}
+			tag.Name = child.name
+			tag.Help = child.help
+			tag.Cmd = true
+			fv := reflect.Indirect(reflect.ValueOf(child.cmd))
+			ft := reflect.StructField{Name: child.name, Type: fv.Type()}
+			if err := buildChild(k, node, CommandNode, v, ft, fv, tag, child.name, seenFlags); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		tag, err := child.builder.tag()
+		if err != nil {
+			return nil, err
+		}
+		sub, err := buildCommand(k, child.builder, CommandNode, seenFlags)
+		if err != nil {
+			return nil, err
+		}
+		ft := reflect.StructField{Name: child.builder.name, Type: v.Type()}
+		if err := attachChild(k, node, sub, v, ft, tag, child.builder.name); err != nil {
+			return nil, err
+		}
+	}
+	if err := finishNode(k, node, v, seenFlags); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
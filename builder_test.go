@@ -0,0 +1,124 @@
+package kong_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/alecthomas/kong"
+)
+
+func TestCommandBuilder(t *testing.T) {
+	var (
+		debug   bool
+		region  string
+		replica int
+		targets []string
+		ran     string
+	)
+	deploy := kong.NewCommand("deploy").
+		Help("Deploy the application.").
+		Tags(`aliases:"d"`).
+		Flag("region", kong.Var(&region), `help:"Region to deploy to."`, `short:"r"`, `default:"us-east-1"`).
+		Flag("replicas", kong.Var(&replica), `env:"REPLICAS"`).
+		Arg("targets", kong.Var(&targets), `help:"Targets."`).
+		Run(func(ctx *kong.Context) error {
+			ran = ctx.Command()
+			return nil
+		})
+	app := kong.NewCommand("").
+		Flag("debug", kong.Var(&debug)).
+		Command(deploy)
+	p := mustNew(t, app)
+	ctx, err := p.Parse([]string{"--debug", "d", "-r", "eu-west-1", "--replicas=3", "web", "db"})
+	assert.NoError(t, err)
+	assert.True(t, debug)
+	assert.Equal(t, "eu-west-1", region)
+	assert.Equal(t, 3, replica)
+	assert.Equal(t, []string{"web", "db"}, targets)
+	assert.Equal(t, "deploy <targets>", ctx.Command())
+	assert.NoError(t, ctx.Run())
+	assert.Equal(t, "deploy <targets>", ran)
+
+	_, err = p.Parse([]string{"deploy", "web"})
+	assert.NoError(t, err)
+	assert.Equal(t, "us-east-1", region)
+
+	_, err = p.Parse([]string{"deploy"})
+	assert.EqualError(t, err, "missing positional arguments <targets>")
+}
+
+func TestCommandBuilderMixedWithStructs(t *testing.T) {
+	var cli struct {
+		Verbose bool
+		Status  struct{} `cmd:"" help:"Show status."`
+	}
+	type logsCmd struct {
+		Follow bool `short:"f"`
+	}
+	var (
+		logs  logsCmd
+		name  string
+		bound *kong.Context
+	)
+	plugin := kong.NewCommand("plugin").
+		Help("Manage plugins.").
+		Group("Plugins").
+		Command(kong.NewCommand("install").
+			Arg("name", kong.Var(&name)).
+			Run(func(ctx *kong.Context) error {
+				bound = ctx
+				return nil
+			})).
+		Struct("logs", "Show plugin logs.", &logs)
+	w := &strings.Builder{}
+	p := mustNew(t, &cli, kong.Commands(plugin), kong.Writers(w, w), kong.Exit(func(int) {}))
+
+	ctx, err := p.Parse([]string{"--verbose", "plugin", "install", "foo"})
+	assert.NoError(t, err)
+	assert.True(t, cli.Verbose)
+	assert.Equal(t, "foo", name)
+	assert.NoError(t, ctx.Run())
+	assert.Equal(t, ctx, bound)
+
+	_, err = p.Parse([]string{"plugin", "logs", "-f"})
+	assert.NoError(t, err)
+	assert.True(t, logs.Follow)
+
+	_, _ = p.Parse([]string{"--help"})
+	assert.Contains(t, w.String(), "status [flags]\n    Show status.")
+	assert.Contains(t, w.String(), "Plugins\n  plugin install <name>")
+	assert.Contains(t, w.String(), "plugin logs [flags]\n    Show plugin logs.")
+}
+
+func TestCommandBuilderDynamicCommand(t *testing.T) {
+	var cli struct{}
+	var value string
+	cmd := kong.NewCommand("ignored").Flag("value", kong.Var(&value))
+	p := mustNew(t, &cli, kong.DynamicCommand("renamed", "Renamed.", "", cmd, `aliases:"rn"`))
+	_, err := p.Parse([]string{"rn", "--value=x"})
+	assert.NoError(t, err)
+	assert.Equal(t, "x", value)
+}
+
+func TestCommandBuilderErrors(t *testing.T) {
+	var a, b string
+	_, err := kong.New(kong.NewCommand("").
+		Flag("flag", kong.Var(&a)).
+		Flag("flag", kong.Var(&b)))
+	assert.EqualError(t, err, "<anonymous command>.flag: duplicate flag --flag")
+
+	_, err = kong.New(kong.NewCommand("").Command(kong.NewCommand("cmd").Run("not a function")))
+	assert.EqualError(t, err, "cmd: cmd: expected a Run function returning an error but got string")
+
+	var cli struct{}
+	_, err = kong.New(&cli, kong.Commands(kong.NewCommand("cmd").Struct("sub", "", cli)))
+	assert.EqualError(t, err, "cmd: sub: expected a pointer to a struct but got struct {}")
+
+	var args []string
+	_, err = kong.New(kong.NewCommand("").
+		Arg("args", kong.Var(&args)).
+		Command(kong.NewCommand("cmd")))
+	assert.Error(t, err)
+}
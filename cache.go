@@ -0,0 +1,214 @@
+package kong
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"hash/fnv"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// modelCacheVersion is included in checksums, and must be changed whenever the parsing of tags
+// changes, to invalidate existing caches. Changes to the fields of CachedTag are covered by
+// modelCacheSchema.
+const modelCacheVersion = "4"
+
+// modelCacheSchema describes the fields of a CachedTag, and is included in checksums so that
+// existing caches are invalidated when the fields written by GenerateModelCache change.
+var modelCacheSchema = typeSchema(reflect.TypeOf(CachedTag{}))
+
+// typeSchema describes typ, including the names and types of the exported fields of structs.
+func typeSchema(typ reflect.Type) string {
+	switch typ.Kind() {
+	case reflect.Struct:
+		fields := []string{}
+		for i := 0; i < typ.NumField(); i++ {
+			if field := typ.Field(i); field.IsExported() {
+				fields = append(fields, field.Name+" "+typeSchema(field.Type))
+			}
+		}
+		return typ.String() + "{" + strings.Join(fields, "; ") + "}"
+	case reflect.Ptr:
+		return "*" + typeSchema(typ.Elem())
+	case reflect.Slice:
+		return "[]" + typeSchema(typ.Elem())
+	case reflect.Array:
+		return "[" + strconv.Itoa(typ.Len()) + "]" + typeSchema(typ.Elem())
+	case reflect.Map:
+		return "map[" + typeSchema(typ.Key()) + "]" + typeSchema(typ.Elem())
+	default:
+		return typ.String()
+	}
+}
+
+// ModelCache contains the parsed tags of the fields of a grammar, keyed by struct type and field
+// name, so that New can skip parsing them.
+//
+// A ModelCache is usually generated with GenerateModelCache from a "go generate" step, and passed
+// to New with WithModelCache.
+type ModelCache map[string]CachedTag
+
+// CachedTag is the parsed tag of a field in a ModelCache.
+type CachedTag struct {
+	// Checksum of the field's type and tag when the cache was generated. If the field no longer
+	// matches, the cached tag is ignored and the field's tag is parsed.
+	Checksum uint64
+	Tag      Tag
+	Items    map[string][]string
+}
+
+// WithModelCache uses the tags in cache rather than parsing them, for fields of the grammar that
+// have not changed since the cache was generated.
+func WithModelCache(cache ModelCache) Option {
+	return OptionFunc(func(k *Kong) error {
+		k.modelCache = cache
+		return nil
+	})
+}
+
+// NewModelCache builds grammar with options, including any lazily built commands, and returns the
+// parsed tags of its fields.
+func NewModelCache(grammar any, options ...Option) (ModelCache, error) {
+	cache := ModelCache{}
+	options = append(options, OptionFunc(func(k *Kong) error {
+		k.modelCache = cache
+		k.recordModelCache = true
+		return nil
+	}))
+	k, err := New(grammar, options...)
+	if err != nil {
+		return nil, err
+	}
+	if err := k.Model.Node.Build(); err != nil {
+		return nil, err
+	}
+	return cache, nil
+}
+
+// GenerateModelCache writes Go source declaring the variable "name" in package "pkg", containing
+// the ModelCache of grammar, eg. from a program run by "go generate":
+//
+//	err := kong.GenerateModelCache(w, "main", "modelCache", &CLI{})
+//
+// The variable can then be passed to New with WithModelCache(modelCache).
+func GenerateModelCache(w io.Writer, pkg, name string, grammar any, options ...Option) error {
+	cache, err := NewModelCache(grammar, options...)
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(cache))
+	for key := range cache {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "// Code generated by kong.GenerateModelCache. DO NOT EDIT.\n\n")
+	fmt.Fprintf(buf, "package %s\n\nimport \"github.com/alecthomas/kong\"\n\n", pkg)
+	fmt.Fprintf(buf, "var %s = kong.ModelCache{\n", name)
+	for _, key := range keys {
+		cached := cache[key]
+		fmt.Fprintf(buf, "%q: {\nChecksum: %#x,\nTag: kong.Tag{", key, cached.Checksum)
+		tv := reflect.ValueOf(cached.Tag)
+		for i := 0; i < tv.NumField(); i++ {
+			field := tv.Type().Field(i)
+			if !field.IsExported() || tv.Field(i).IsZero() {
+				continue
+			}
+			if field.Type.Kind() == reflect.Int32 && tv.Field(i).Int() > 0 {
+				// Runes, other than -1 for none.
+				fmt.Fprintf(buf, "%s: %q, ", field.Name, tv.Field(i).Interface())
+			} else {
+				fmt.Fprintf(buf, "%s: %#v, ", field.Name, tv.Field(i).Interface())
+			}
+		}
+		fmt.Fprintf(buf, "},\nItems: %#v,\n},\n", cached.Items)
+	}
+	fmt.Fprintf(buf, "}\n")
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(source)
+	return err
+}
+
+// parseFieldTag parses the tag of a field, using or recording it in the model cache.
+func (k *Kong) parseFieldTag(parent reflect.Value, ft reflect.StructField) (*Tag, error) {
+	// Fields of commands built with a CommandBuilder are not part of a struct type.
+	if k.modelCache == nil || ft.Index == nil {
+		return parseTag(parent, ft)
+	}
+	key := k.modelCacheKey(parent.Type()) + "." + ft.Name
+	checksum := modelCacheChecksum(ft)
+	if cached, ok := k.modelCache[key]; ok && cached.Checksum == checksum && !k.recordModelCache {
+		tag := cached.Tag.clone()
+		tag.items = cached.Items
+		// Compiled patterns are not cached.
+		if err := tag.compileKeyFormat(); err != nil {
+			return nil, err
+		}
+		return tag, nil
+	}
+	tag, err := parseTag(parent, ft)
+	if err != nil {
+		return nil, err
+	}
+	if k.recordModelCache {
+		k.modelCache[key] = CachedTag{Checksum: checksum, Tag: *tag.clone(), Items: tag.items}
+	}
+	return tag, nil
+}
+
+// modelCacheKey returns the key of a struct type in the model cache.
+func (k *Kong) modelCacheKey(typ reflect.Type) string {
+	if typ.Name() != "" {
+		return typ.PkgPath() + "." + typ.Name()
+	}
+	// The string of an anonymous struct includes all of its fields, so hash it, once.
+	if key, ok := k.modelCacheKeys[typ]; ok {
+		return key
+	}
+	h := fnv.New64a()
+	_, _ = io.WriteString(h, typ.String())
+	key := "struct#" + strconv.FormatUint(h.Sum64(), 16)
+	if k.modelCacheKeys == nil {
+		k.modelCacheKeys = map[reflect.Type]string{}
+	}
+	k.modelCacheKeys[typ] = key
+	return key
+}
+
+// modelCacheChecksum returns a checksum of everything the parsed tag of a field depends on.
+func modelCacheChecksum(ft reflect.StructField) uint64 {
+	h := fnv.New64a()
+	signature, _ := maybeGetSignature(ft.Type)
+	for _, s := range []string{modelCacheVersion, modelCacheSchema, ft.Type.String(), string(ft.Tag), string(signature)} {
+		_, _ = io.WriteString(h, s)
+		_, _ = h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
+
+// clone returns a copy of t. The slices that may be modified while building are copied, while
+// maps, which are not modified after parsing, are shared.
+func (t *Tag) clone() *Tag {
+	out := *t
+	out.Envs = cloneStrings(t.Envs)
+	out.Xor = cloneStrings(t.Xor)
+	out.And = cloneStrings(t.And)
+	out.Aliases = cloneStrings(t.Aliases)
+	out.RequiredKeys = cloneStrings(t.RequiredKeys)
+	out.Inherit = cloneStrings(t.Inherit)
+	return &out
+}
+
+func cloneStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string{}, s...)
+}
@@ -0,0 +1,82 @@
+package kong_test
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/alecthomas/kong"
+)
+
+type cacheCLI struct {
+	Debug bool `short:"d" help:"Enable ${what}." env:"DEBUG"`
+	Serve struct {
+		Port  int            `default:"8080" help:"Port."`
+		Label map[string]int `keyenum:"a,b"`
+		Names map[string]int `keyformat:"[a-z]+"`
+		Dir   string         `arg:"" optional:""`
+	} `cmd:"" help:"Serve files." aliases:"s"`
+}
+
+func TestModelCache(t *testing.T) {
+	help := func(options ...kong.Option) string {
+		t.Helper()
+		var cli cacheCLI
+		w := &strings.Builder{}
+		options = append(options, kong.Vars{"what": "debugging"}, kong.Writers(w, w), kong.Exit(func(int) {}))
+		p := mustNew(t, &cli, options...)
+		_, _ = p.Parse([]string{"serve", "--help"})
+		ctx, err := p.Parse([]string{"-d", "s", "--port=1", "--label=a=2", "dir"})
+		assert.NoError(t, err)
+		assert.Equal(t, "serve <dir>", ctx.Command())
+		assert.True(t, cli.Debug)
+		assert.Equal(t, 1, cli.Serve.Port)
+		assert.Equal(t, map[string]int{"a": 2}, cli.Serve.Label)
+		return w.String()
+	}
+	cache, err := kong.NewModelCache(&cacheCLI{}, kong.Vars{"what": "debugging"})
+	assert.NoError(t, err)
+	key := "github.com/alecthomas/kong_test.cacheCLI.Debug"
+	assert.Equal(t, "Enable ${what}.", cache[key].Tag.Help)
+	assert.Equal(t, help(), help(kong.WithModelCache(cache)))
+
+	// Key formats are compiled when cached tags are used.
+	var cli cacheCLI
+	_, err = mustNew(t, &cli, kong.Vars{"what": "debugging"}, kong.WithModelCache(cache)).Parse([]string{"serve", "--names=A=1"})
+	assert.EqualError(t, err, `--names: map key "A" does not match "[a-z]+"`)
+
+	// Cached tags are used in place of parsing.
+	cached := cache[key]
+	cached.Tag.Help = "Cached."
+	cache[key] = cached
+	assert.Contains(t, help(kong.WithModelCache(cache)), "Cached ($DEBUG)")
+
+	// Tags are parsed if the field has changed since the cache was generated.
+	cached.Checksum++
+	cache[key] = cached
+	assert.Contains(t, help(kong.WithModelCache(cache)), "Enable debugging ($DEBUG)")
+}
+
+func TestGenerateModelCache(t *testing.T) {
+	w := &strings.Builder{}
+	err := kong.GenerateModelCache(w, "main", "modelCache", &cacheCLI{}, kong.Vars{"what": "debugging"})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(w.String(), "// Code generated by kong.GenerateModelCache. DO NOT EDIT.\n"))
+	assert.Contains(t, w.String(), "var modelCache = kong.ModelCache{")
+	assert.Contains(t, w.String(), `"github.com/alecthomas/kong_test.cacheCLI.Debug": {`)
+	assert.Contains(t, w.String(), `kong.Tag{Help: "Enable ${what}.", TypeName: "bool", Envs: []string{"DEBUG"}, Short: 'd', `)
+	assert.NotContains(t, w.String(), "ValueTypes")
+	// The generated source must compile.
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "cache.go", w.String(), 0)
+	assert.NoError(t, err)
+	config := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err = config.Check("main", fset, []*ast.File{file}, nil)
+	assert.NoError(t, err)
+}
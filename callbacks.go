@@ -168,10 +168,13 @@ func getMethods(value reflect.Value, name string) (methods []reflect.Value) {
 
 // walkEmbedded calls visit on v and recursively on every exported field
 // of v that is either a standard Go anonymous field or tagged `embed:""`.
-// Pointer values are dereferenced before traversal; nil/invalid pointers
-// are skipped. [Plugins] are descended into element-by-element, matching how
+// Interface and pointer values are dereferenced before traversal; nil/invalid
+// values are skipped. [Plugins] are descended into element-by-element, matching how
 // [flattenedFields] treats them at build time.
 func walkEmbedded(value reflect.Value, visit func(reflect.Value)) {
+	if value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
 	if value.Kind() == reflect.Pointer {
 		value = value.Elem()
 	}
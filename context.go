@@ -91,6 +91,14 @@ type Context struct {
 	bindings  bindings
 	resolvers []Resolver // Extra context-specific resolvers.
 	scan      *Scanner
+
+	sequenceStart int   // Index into Path of the first command of a sequence.
+	sequence      []int // Indexes into Path of the following commands of a sequence.
+	aliasesDone   bool  // An alias has been expanded, or may no longer be.
+	dashDash      bool  // Parsing was ended by "--", after which global flags are not extracted.
+	runErr        error // Error returned by the Run() methods, for AfterRun hooks.
+
+	mapKeys map[*Value]map[any]bool // Map keys decoded into each value, for "nodupes".
 }
 
 // Trace path of "args" through the grammar tree.
@@ -108,6 +116,7 @@ func Trace(k *Kong, args []string) (*Context, error) {
 			{App: k.Model, Flags: k.Model.Flags, remainder: s.PeekAll()},
 		},
 		values:   map[*Value]reflect.Value{},
+		mapKeys:  map[*Value]map[any]bool{},
 		scan:     s,
 		bindings: bindings{},
 	}
@@ -198,10 +207,31 @@ func (c *Context) Validate() error { //nolint: gocyclo
 		for _, value := range node.Values() {
 			ok := atLeastOneEnvSet(value.Tag.Envs)
 			if value.Enum != "" && (!value.Required || value.HasDefault || (len(value.Tag.Envs) != 0 && ok)) {
-				if err := checkEnum(value, value.Target); err != nil {
+				if err := checkEnum(c.suggestOptions, value, value.Target); err != nil {
 					return err
 				}
 			}
+			if target := reflect.Indirect(value.Target); value.Set && value.Tag.MinItems != 0 && target.Kind() == reflect.Slice {
+				if target.Len() < value.Tag.MinItems {
+					return fmt.Errorf("%s: expected at least %d values but got %d", value.ShortSummary(), value.Tag.MinItems, target.Len())
+				}
+			}
+			if target := reflect.Indirect(value.Target); value.Set && len(value.Tag.RequiredKeys) != 0 && target.Kind() == reflect.Map {
+				for _, key := range value.Tag.RequiredKeys {
+					if !hasMapKey(target, key) {
+						// The key may have been misspelt.
+						keys := []string{}
+						for _, mapKey := range target.MapKeys() {
+							keys = append(keys, fmt.Sprint(mapKey.Interface()))
+						}
+						sort.Strings(keys)
+						if similar := c.suggestOptions.Suggest(key, keys); len(similar) > 0 {
+							return fmt.Errorf("%s: missing required key %q, is %q misspelt?", value.ShortSummary(), key, similar[0])
+						}
+						return fmt.Errorf("%s: missing required key %q", value.ShortSummary(), key)
+					}
+				}
+			}
 		}
 	}
 	for _, el := range c.Path {
@@ -236,7 +266,15 @@ func (c *Context) Validate() error { //nolint: gocyclo
 		}
 	}
 	for _, resolver := range c.combineResolvers() {
-		if err := resolver.Validate(c.Model); err != nil {
+		validate := resolver.Validate
+		if suggesting, ok := resolver.(suggestingValidator); ok {
+			var keys []string
+			if c.aliasesKey != "" {
+				keys = append(keys, c.aliasesKey)
+			}
+			validate = func(app *Application) error { return suggesting.validate(app, c.suggestOptions, keys) }
+		}
+		if err := validate(c.Model); err != nil {
 			return err
 		}
 	}
@@ -250,14 +288,24 @@ func (c *Context) Validate() error { //nolint: gocyclo
 			value = path.Positional
 		}
 		if value != nil && value.Tag.Enum != "" {
-			if err := checkEnum(value, value.Target); err != nil {
+			if err := checkEnum(c.suggestOptions, value, value.Target); err != nil {
 				return err
 			}
 		}
-		if err := checkMissingFlags(path.Flags); err != nil {
+		if err := checkMissingFlags(c.inheritedFlags(path)); err != nil {
 			return err
 		}
 	}
+	for _, sequence := range c.Sequence() {
+		if err := sequence.validateSelected(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateSelected checks the terminal node of the context.
+func (c *Context) validateSelected() error {
 	// Check the terminal node.
 	node := c.Selected()
 	if node == nil {
@@ -291,6 +339,25 @@ func (c *Context) Validate() error { //nolint: gocyclo
 	return nil
 }
 
+// inheritedFlags returns the flags of path that apply to the selected command, or to any of the
+// commands of a sequence that path is part of.
+func (c *Context) inheritedFlags(path *Path) []*Flag {
+	selected, owner := c.selectedCommands(path), path.Node()
+	if len(selected) == 0 || owner == nil {
+		return path.Flags
+	}
+	flags := make([]*Flag, 0, len(path.Flags))
+	for _, flag := range path.Flags {
+		for _, command := range selected {
+			if command.inherits(owner, flag) {
+				flags = append(flags, flag)
+				break
+			}
+		}
+	}
+	return flags
+}
+
 // Flags returns the accumulated available flags.
 func (c *Context) Flags() (flags []*Flag) {
 	for _, trace := range c.Path {
@@ -349,7 +416,7 @@ func (c *Context) Reset() error {
 		if !ok {
 			return next(nil)
 		}
-		err := value.Reset()
+		err := value.reset(c.Kong)
 		if err != nil && !selected[value] {
 			// An envar shared with a node outside the selected command path
 			// may not parse there; that must not fail this parse.
@@ -375,15 +442,31 @@ func (c *Context) selectedValues() map[*Value]bool {
 }
 
 func (c *Context) endParsing() {
+	c.aliasesDone = true
 	args := []string{}
+	// Flags already extracted from the arguments by an earlier call.
+	extracted := []Token{}
 	for {
 		token := c.scan.Pop()
 		if token.Type == EOLToken {
 			break
 		}
+		if token.Type.IsAny(FlagToken, ShortFlagToken, FlagValueToken) {
+			extracted = append(extracted, token)
+			continue
+		}
 		args = append(args, token.String())
 	}
+	// Global flags that cross the end of parsing are parsed after the remaining arguments.
+	if !c.dashDash {
+		var globals []Token
+		args, globals = c.extractGlobalFlags(args)
+		extracted = append(globals, extracted...)
+	}
 	// Note: tokens must be pushed in reverse order.
+	for i := len(extracted) - 1; i >= 0; i-- {
+		c.scan.PushToken(extracted[i])
+	}
 	for i := range args {
 		c.scan.PushTyped(args[len(args)-1-i], PositionalArgumentToken)
 	}
@@ -393,6 +476,9 @@ func (c *Context) endParsing() {
 func (c *Context) trace(node *Node) (err error) { //nolint: gocyclo
 	positional := 0
 	node.Active = true
+	if node == c.sequenceNode && c.sequenceStart == 0 {
+		c.sequenceStart = len(c.Path)
+	}
 
 	flags := []*Flag{}
 	flagNode := node
@@ -416,6 +502,14 @@ func (c *Context) trace(node *Node) (err error) { //nolint: gocyclo
 		case UntypedToken:
 			switch v := token.Value.(type) {
 			case string:
+				if tokens, ok := c.flagSyntax.split(v); ok && c.isFlag(flags, tokens[0].String()) {
+					c.scan.Pop()
+					// Note: tokens must be pushed in reverse order.
+					for i := len(tokens) - 1; i >= 0; i-- {
+						c.scan.PushToken(tokens[i])
+					}
+					continue
+				}
 
 				switch {
 				case v == "-":
@@ -424,8 +518,14 @@ func (c *Context) trace(node *Node) (err error) { //nolint: gocyclo
 					c.scan.Pop()
 					c.scan.PushTyped(token.Value, PositionalArgumentToken)
 
+				// Ends a command in a sequence.
+				case c.sequenceSeparator != "" && v == c.sequenceSeparator && c.inSequence(node):
+					c.scan.Pop()
+					return c.restartSequence(node)
+
 				// Indicates end of parsing. All remaining arguments are treated as positional arguments only.
 				case v == "--":
+					c.dashDash = true
 					c.endParsing()
 
 					// Pop the -- token unless the next positional argument accepts passthrough arguments.
@@ -458,6 +558,9 @@ func (c *Context) trace(node *Node) (err error) { //nolint: gocyclo
 			}
 
 		case ShortFlagTailToken:
+			if c.noShortFlagClustering {
+				return fmt.Errorf("unexpected %q after short flag, short flags can not be combined", token.Value)
+			}
 			c.scan.Pop()
 			// Note: tokens must be pushed in reverse order.
 			if tail := token.String()[1:]; tail != "" {
@@ -465,19 +568,10 @@ func (c *Context) trace(node *Node) (err error) { //nolint: gocyclo
 			}
 			c.scan.PushTyped(token.String()[0:1], ShortFlagToken)
 
-		case FlagToken:
-			if err := c.parseFlag(flags, token.String()); err != nil {
-				if isUnknownFlagError(err) && positional < len(node.Positional) && node.Positional[positional].PassthroughMode == PassThroughModeAll {
-					c.scan.Pop()
-					c.scan.PushTyped(token.String(), PositionalArgumentToken)
-				} else {
-					return err
-				}
-			}
-
-		case ShortFlagToken:
-			if err := c.parseFlag(flags, token.String()); err != nil {
-				if isUnknownFlagError(err) && positional < len(node.Positional) && node.Positional[positional].PassthroughMode == PassThroughModeAll {
+		case FlagToken, ShortFlagToken:
+			passthrough := positional < len(node.Positional) && node.Positional[positional].PassthroughMode == PassThroughModeAll
+			if err := c.parseFlag(node, flags, token.String(), passthrough); err != nil {
+				if isUnknownFlagError(err) && passthrough {
 					c.scan.Pop()
 					c.scan.PushTyped(token.String(), PositionalArgumentToken)
 				} else {
@@ -500,7 +594,7 @@ func (c *Context) trace(node *Node) (err error) { //nolint: gocyclo
 				}
 
 				arg.Active = true
-				err := arg.Parse(c.scan, c.getValue(arg))
+				err := c.parsePositional(node, arg)
 				if err != nil {
 					return err
 				}
@@ -513,6 +607,23 @@ func (c *Context) trace(node *Node) (err error) { //nolint: gocyclo
 				break
 			}
 
+			// A command without sub-commands is complete, so start the next command of a sequence.
+			if len(node.Children) == 0 && c.inSequence(node) {
+				return c.restartSequence(node)
+			}
+
+			// The first command of the application may be an alias.
+			if node == c.Model.Node && !c.aliasesDone {
+				c.aliasesDone = true
+				expanded, err := c.expandAlias()
+				if err != nil {
+					return err
+				}
+				if expanded {
+					continue
+				}
+			}
+
 			// Assign token value to a branch name if tagged as an alias
 			// An alias will be ignored in the case of an existing command
 			cmds := make(map[string]bool)
@@ -524,19 +635,34 @@ func (c *Context) trace(node *Node) (err error) { //nolint: gocyclo
 			for _, branch := range node.Children {
 				for _, a := range branch.Aliases {
 					_, ok := cmds[a]
-					if token.Value == a && !ok {
+					if c.equalNames(token.String(), a) && !ok {
 						token.Value = branch.Name
 						break
 					}
 				}
 			}
 
+			if c.allowAbbreviations {
+				name, err := expandCommandAbbreviation(node, token.String(), c.caseInsensitive)
+				if err != nil {
+					return err
+				}
+				token.Value = name
+			}
+
 			// After positional arguments have been consumed, check commands next...
 			for _, branch := range node.Children {
 				if branch.Type == CommandNode && !branch.Hidden {
 					candidates = append(candidates, branch.Name)
+					candidates = append(candidates, branch.Aliases...)
 				}
-				if branch.Type == CommandNode && branch.Name == token.Value {
+				if branch.Type == CommandNode && c.equalNames(branch.Name, token.String()) && !c.isAliasCommand(node, branch) {
+					if err := c.checkSequenceRepeat(branch); err != nil {
+						return err
+					}
+					if err := branch.buildLazy(); err != nil {
+						return err
+					}
 					c.scan.Pop()
 					c.Path = append(c.Path, &Path{
 						Parent:    node,
@@ -552,7 +678,7 @@ func (c *Context) trace(node *Node) (err error) { //nolint: gocyclo
 			for _, branch := range node.Children {
 				if branch.Type == ArgumentNode {
 					arg := branch.Argument
-					if err := arg.Parse(c.scan, c.getValue(arg)); err == nil {
+					if err := arg.parse(c.Kong, c.scan, c.getValue(arg), c.seenKeys(arg)); err == nil {
 						c.Path = append(c.Path, &Path{
 							Parent:    node,
 							Argument:  branch,
@@ -564,6 +690,11 @@ func (c *Context) trace(node *Node) (err error) { //nolint: gocyclo
 				}
 			}
 
+			// Then a dynamically selected default command, which may consume the token...
+			if selected, err := c.selectDefault(node); selected || err != nil {
+				return err
+			}
+
 			// If there is a default command that allows args and nothing else
 			// matches, take the branch of the default command
 			if node.DefaultCmd != nil && node.DefaultCmd.Tag.Default == "withargs" {
@@ -576,7 +707,7 @@ func (c *Context) trace(node *Node) (err error) { //nolint: gocyclo
 				return c.trace(node.DefaultCmd)
 			}
 
-			return findPotentialCandidates(token.String(), candidates, "unexpected argument %s", token)
+			return findPotentialCandidates(c.suggestOptions, token.String(), candidates, "unexpected argument %s", token)
 		default:
 			return fmt.Errorf("unexpected token %s", token)
 		}
@@ -597,6 +728,9 @@ func (c *Context) maybeSelectDefault(flags []*Flag, node *Node) error {
 			return nil
 		}
 	}
+	if selected, err := c.selectDefault(node); selected || err != nil {
+		return err
+	}
 	if node.DefaultCmd != nil {
 		c.Path = append(c.Path, &Path{
 			Parent:    node.DefaultCmd,
@@ -608,6 +742,35 @@ func (c *Context) maybeSelectDefault(flags []*Flag, node *Node) error {
 	return nil
 }
 
+// selectDefault adds the command selected by the DefaultFunc of node, if any, to the path and
+// traces it. Returns false if no command is selected.
+func (c *Context) selectDefault(node *Node) (bool, error) {
+	if node.DefaultFunc == nil {
+		return false, nil
+	}
+	args := []string{}
+	for _, token := range c.scan.PeekAll() {
+		args = append(args, token.String())
+	}
+	selected, err := node.DefaultFunc(c, node, args)
+	if err != nil || selected == nil {
+		return false, err
+	}
+	if selected.Parent != node || selected.Type != CommandNode {
+		return false, fmt.Errorf("default command %q is not a sub-command of %s", selected.Name, nodeDescription(node))
+	}
+	if err := selected.buildLazy(); err != nil {
+		return false, err
+	}
+	c.Path = append(c.Path, &Path{
+		Parent:    node,
+		Command:   selected,
+		Flags:     selected.Flags,
+		remainder: c.scan.PeekAll(),
+	})
+	return true, c.trace(selected)
+}
+
 // Resolve walks through the traced path, applying resolvers to any unset flags.
 func (c *Context) Resolve() error {
 	resolvers := c.combineResolvers()
@@ -642,7 +805,7 @@ func (c *Context) Resolve() error {
 
 			scan := Scan().PushTyped(selected, FlagValueToken)
 			delete(c.values, flag.Value)
-			err := flag.Parse(scan, c.getValue(flag.Value))
+			err := flag.parse(c.Kong, scan, c.getValue(flag.Value), c.seenKeys(flag.Value))
 			if err != nil {
 				return err
 			}
@@ -683,6 +846,16 @@ func (c *Context) getValue(value *Value) reflect.Value {
 	return v
 }
 
+// seenKeys returns the map keys decoded into value by this parse, for "nodupes".
+func (c *Context) seenKeys(value *Value) map[any]bool {
+	seen, ok := c.mapKeys[value]
+	if !ok {
+		seen = map[any]bool{}
+		c.mapKeys[value] = seen
+	}
+	return seen
+}
+
 // ApplyDefaults if they are not already set.
 func (c *Context) ApplyDefaults() error {
 	return Visit(c.Model.Node, func(node Visitable, next Next) error {
@@ -697,7 +870,7 @@ func (c *Context) ApplyDefaults() error {
 		default:
 		}
 		if value != nil {
-			if err := value.ApplyDefault(); err != nil {
+			if err := value.applyDefault(c.Kong); err != nil {
 				return err
 			}
 		}
@@ -750,37 +923,56 @@ func flipBoolValue(value reflect.Value) error {
 	return fmt.Errorf("cannot negate a value of %s", value.Type().String())
 }
 
-func (c *Context) parseFlag(flags []*Flag, match string) (err error) {
+// parseFlag parses the flag "match" from flags. If passthrough is true an unknown flag will be passed
+// through, so the commands accepting it are not looked up for the error.
+func (c *Context) parseFlag(node *Node, flags []*Flag, match string, passthrough bool) (err error) {
+	if c.allowAbbreviations {
+		if match, err = expandFlagAbbreviation(flags, match, c.caseInsensitive); err != nil {
+			return &unknownFlagError{Cause: err}
+		}
+	}
 	candidates := []string{}
 
 	for _, flag := range flags {
 		long := "--" + flag.Name
-		matched := long == match
-		candidates = append(candidates, long)
+		matched := c.equalNames(long, match)
+		if !flag.Hidden {
+			candidates = append(candidates, long)
+		}
 		if flag.Short != 0 {
 			short := "-" + string(flag.Short)
 			matched = matched || (short == match)
-			candidates = append(candidates, short)
+			if !flag.Hidden {
+				candidates = append(candidates, short)
+			}
 		}
 		for _, alias := range flag.Aliases {
 			aliasFlag := "--" + alias
 			if utf8.RuneCountInString(alias) == 1 {
 				aliasFlag = "-" + alias
+				matched = matched || (aliasFlag == match)
+			} else {
+				matched = matched || c.equalNames(aliasFlag, match)
+			}
+			if !flag.Hidden {
+				candidates = append(candidates, aliasFlag)
 			}
-			matched = matched || (aliasFlag == match)
-			candidates = append(candidates, aliasFlag)
 		}
 
 		neg := negatableFlagName(flag.Name, flag.Tag.Negatable)
-		if !matched && match != neg {
+		if neg != "" && !flag.Hidden {
+			candidates = append(candidates, neg)
+		}
+		negated := neg != "" && c.equalNames(match, neg)
+		if !matched && !negated {
 			continue
 		}
 		// Found a matching flag.
 		c.scan.Pop()
-		if match == neg && flag.Tag.Negatable != "" {
+		if negated {
 			flag.Negated = true
 		}
-		err := flag.Parse(c.scan, c.getValue(flag.Value))
+		err := flag.parse(c.Kong, c.scan, c.getValue(flag.Value), c.seenKeys(flag.Value))
 		if err != nil {
 			var expected *expectedError
 			if errors.As(err, &expected) && expected.token.InferredType().IsAny(FlagToken, ShortFlagToken) {
@@ -802,7 +994,88 @@ func (c *Context) parseFlag(flags []*Flag, match string) (err error) {
 		})
 		return nil
 	}
-	return &unknownFlagError{Cause: findPotentialCandidates(match, candidates, "unknown flag %s", match)}
+	if passthrough {
+		return &unknownFlagError{Cause: fmt.Errorf("unknown flag %s", match)}
+	}
+	if owners := c.flagOwners(node, match); len(owners) > 0 {
+		msg := fmt.Sprintf("unknown flag %s; %s is a flag of %s", match, match, strings.Join(owners, " or "))
+		if node.Type != ApplicationNode {
+			msg += fmt.Sprintf(", not %q", commandPath(node))
+		}
+		return &unknownFlagError{Cause: errors.New(msg)}
+	}
+	return &unknownFlagError{Cause: findPotentialCandidates(c.suggestOptions, match, candidates, "unknown flag %s", match)}
+}
+
+// equalNames compares long flag, command or alias names, ignoring case if the CaseInsensitive()
+// option is enabled.
+func (c *Context) equalNames(a, b string) bool {
+	if c.caseInsensitive {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// flagOwners returns the descriptions of visible commands outside the current branch, or ancestors
+// whose flags are not inherited, that accept the flag "match". Lazy commands that have not been
+// built are skipped.
+func (c *Context) flagOwners(node *Node, match string) (owners []string) {
+	inBranch := map[*Node]bool{}
+	for n := node; n != nil; n = n.Parent {
+		inBranch[n] = true
+	}
+	_ = Visit(c.Model.Node, func(visitable Visitable, next Next) error {
+		n, ok := visitable.(*Node)
+		if !ok {
+			return nil
+		}
+		if n.Hidden || n.lazy != nil {
+			return nil
+		}
+		for _, flag := range n.Flags {
+			// Flags of ancestors that are not inherited are also owned by another command.
+			if inBranch[n] && node.inherits(n, flag) {
+				continue
+			}
+			if !flag.Hidden && flagMatches(flag, match) {
+				owners = append(owners, nodeDescription(n))
+				break
+			}
+		}
+		return next(nil)
+	})
+	return owners
+}
+
+// flagMatches returns true if "match" is one of the names of flag, including its short name,
+// aliases and negation.
+func flagMatches(flag *Flag, match string) bool {
+	if match == "--"+flag.Name || (flag.Short != 0 && match == "-"+string(flag.Short)) {
+		return true
+	}
+	for _, alias := range flag.Aliases {
+		if utf8.RuneCountInString(alias) == 1 {
+			if match == "-"+alias {
+				return true
+			}
+		} else if match == "--"+alias {
+			return true
+		}
+	}
+	return flag.Tag.Negatable != "" && match == negatableFlagName(flag.Name, flag.Tag.Negatable)
+}
+
+// commandPath returns the names of the commands and arguments leading to node, without aliases.
+func commandPath(node *Node) string {
+	parts := []string{}
+	for n := node; n != nil && n.Type != ApplicationNode; n = n.Parent {
+		name := n.Name
+		if n.Type == ArgumentNode {
+			name = "<" + name + ">"
+		}
+		parts = append([]string{name}, parts...)
+	}
+	return strings.Join(parts, " ")
 }
 
 func isUnknownFlagError(err error) bool {
@@ -838,6 +1111,9 @@ func (c *Context) RunNode(node *Node, binds ...any) (err error) {
 	methods := []targetMethod{}
 	for i := 0; node != nil; i, node = i+1, node.Parent {
 		method := getMethod(node.Target, "Run")
+		if cmd, ok := node.Target.Addr().Interface().(*builtCommand); ok {
+			method = cmd.run
+		}
 		methodBinds = methodBinds.clone()
 		for p := node; p != nil; p = p.Parent {
 			methodBinds = methodBinds.add(p.Target.Addr().Interface())
@@ -872,9 +1148,23 @@ func (c *Context) RunNode(node *Node, binds ...any) (err error) {
 
 // Run executes the Run() method on the selected command, which must exist.
 //
+// If several commands were given with the CommandSequence option, each is run in order, stopping
+// at the first error. AfterRun hooks are then called once, after the last command.
+//
 // Any passed values will be bindable to arguments of the target Run() method. Additionally,
 // all parent nodes in the command structure will be bound.
 func (c *Context) Run(binds ...any) (err error) {
+	if sequence := c.Sequence(); len(sequence) > 1 {
+		var runErr error
+		for _, command := range sequence {
+			if runErr = command.RunNode(command.Selected(), binds...); runErr != nil {
+				break
+			}
+		}
+		c.runErr = runErr
+		err = c.Kong.applyHook(c, "AfterRun")
+		return errors.Join(runErr, err)
+	}
 	node := c.Selected()
 	if node == nil {
 		if len(c.Path) == 0 {
@@ -893,10 +1183,25 @@ func (c *Context) Run(binds ...any) (err error) {
 		}
 	}
 	runErr := c.RunNode(node, binds...)
+	c.runErr = runErr
 	err = c.Kong.applyHook(c, "AfterRun")
 	return errors.Join(runErr, err)
 }
 
+// buildHelp builds the model and the help of the commands that help for the selected command,
+// or the application, displays.
+func (c *Context) buildHelp() error {
+	if err := c.Model.Node.Build(); err != nil {
+		return err
+	}
+	if selected := c.Selected(); selected != nil {
+		selected.buildHelp()
+	} else {
+		c.Model.Node.buildHelp()
+	}
+	return nil
+}
+
 // PrintUsage to Kong's stdout.
 //
 // If summary is true, a summarised version of the help will be output.
@@ -907,6 +1212,9 @@ func (c *Context) PrintUsage(summary bool) error {
 }
 
 func (c *Context) printHelp(options HelpOptions) error {
+	if err := c.buildHelp(); err != nil {
+		return err
+	}
 	options.ValueFormatter = c.Kong.helpFormatter
 	return c.help(options, c)
 }
@@ -1051,11 +1359,21 @@ func checkMissingPositionals(positional int, values []*Value) error {
 	return fmt.Errorf("missing positional arguments %s", strings.Join(missing, " "))
 }
 
-func checkEnum(value *Value, target reflect.Value) error {
+// hasMapKey returns true if the map target has a key formatting as key.
+func hasMapKey(target reflect.Value, key string) bool {
+	for _, k := range target.MapKeys() {
+		if fmt.Sprint(k.Interface()) == key {
+			return true
+		}
+	}
+	return false
+}
+
+func checkEnum(suggest SuggestOptions, value *Value, target reflect.Value) error {
 	switch target.Kind() {
 	case reflect.Slice, reflect.Array:
 		for i := 0; i < target.Len(); i++ {
-			if err := checkEnum(value, target.Index(i)); err != nil {
+			if err := checkEnum(suggest, value, target.Index(i)); err != nil {
 				return err
 			}
 		}
@@ -1068,18 +1386,37 @@ func checkEnum(value *Value, target reflect.Value) error {
 		if target.IsNil() {
 			return nil
 		}
-		return checkEnum(value, target.Elem())
+		return checkEnum(suggest, value, target.Elem())
 	default:
 		enumSlice := value.EnumSlice()
 		v := fmt.Sprintf("%v", target)
+		isEnum := false
+		// Compare the names of types implementing Enum rather than their formatted values. A value
+		// that is not one of them, including the zero value, is rejected.
+		if values, ok := enumValues(target.Type()); ok {
+			if name, found := enumName(values, target); found {
+				v, isEnum = name, true
+			}
+		}
 		enums := []string{}
 		for _, enum := range enumSlice {
 			if enum == v {
 				return nil
 			}
+			if value.Tag.EnumFold && strings.EqualFold(enum, v) {
+				// Normalise string values to the case of the enum.
+				if target.Kind() == reflect.String && target.CanSet() && !isEnum {
+					target.SetString(enum)
+				}
+				return nil
+			}
 			enums = append(enums, fmt.Sprintf("%q", enum))
 		}
-		return fmt.Errorf("%s must be one of %s but got %q", value.ShortSummary(), strings.Join(enums, ","), fmt.Sprintf("%v", target.Interface()))
+		suggestion := ""
+		if len(enumSlice) > 1 {
+			suggestion = suggest.didYouMean(v, enumSlice)
+		}
+		return fmt.Errorf("%s must be one of %s but got %q%s", value.ShortSummary(), strings.Join(enums, ","), v, suggestion)
 	}
 }
 
@@ -1157,25 +1494,6 @@ func checkAndMissing(paths []*Path) error {
 	return nil
 }
 
-func findPotentialCandidates(needle string, haystack []string, format string, args ...any) error {
-	if len(haystack) == 0 {
-		return fmt.Errorf(format, args...)
-	}
-	closestCandidates := []string{}
-	for _, candidate := range haystack {
-		if strings.HasPrefix(candidate, needle) || levenshtein(candidate, needle) <= 2 {
-			closestCandidates = append(closestCandidates, fmt.Sprintf("%q", candidate))
-		}
-	}
-	prefix := fmt.Sprintf(format, args...)
-	if len(closestCandidates) == 1 {
-		return fmt.Errorf("%s, did you mean %s?", prefix, closestCandidates[0])
-	} else if len(closestCandidates) > 1 {
-		return fmt.Errorf("%s, did you mean one of %s?", prefix, strings.Join(closestCandidates, ", "))
-	}
-	return fmt.Errorf("%s", prefix)
-}
-
 type validatable interface{ Validate() error }
 type extendedValidatable interface {
 	Validate(kctx *Context) error
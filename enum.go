@@ -0,0 +1,173 @@
+package kong
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// EnumValue is one of the values of an Enum.
+type EnumValue struct {
+	// Name of the value on the command-line.
+	Name string
+	// Help describing the value.
+	Help string
+	// Value assigned to the target, eg. a typed constant. It must be convertible to the type
+	// implementing Enum.
+	Value any
+}
+
+// Enum may be implemented by named types to decode directly into one of a fixed set of values, eg.
+//
+//	type Level int
+//
+//	const (
+//		Debug Level = iota
+//		Info
+//	)
+//
+//	func (Level) Values() []kong.EnumValue {
+//		return []kong.EnumValue{
+//			{Name: "debug", Help: "Verbose logging.", Value: Debug},
+//			{Name: "info", Help: "Normal logging.", Value: Info},
+//		}
+//	}
+//
+// The names of the values are used as the "enum" of flags and arguments of the type, and values
+// with help are listed in the help of the flag or argument.
+type Enum interface {
+	Values() []EnumValue
+}
+
+var enumType = reflect.TypeOf((*Enum)(nil)).Elem()
+
+// enumValues returns the values of typ if it implements Enum.
+func enumValues(typ reflect.Type) ([]EnumValue, bool) {
+	switch {
+	case typ.Kind() == reflect.Ptr:
+		return nil, false
+	case typ.Implements(enumType):
+		return reflect.Zero(typ).Interface().(Enum).Values(), true //nolint: forcetypeassert
+	case reflect.PtrTo(typ).Implements(enumType):
+		return reflect.New(typ).Interface().(Enum).Values(), true //nolint: forcetypeassert
+	}
+	return nil, false
+}
+
+// enumElemValues returns the Enum type and its values for typ, or for the elements of typ if it
+// is a pointer, slice or array.
+func enumElemValues(typ reflect.Type) (reflect.Type, []EnumValue, bool) {
+	for {
+		if values, ok := enumValues(typ); ok {
+			return typ, values, true
+		}
+		switch typ.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			typ = typ.Elem()
+		default:
+			return nil, nil, false
+		}
+	}
+}
+
+// checkEnumValues ensures the values of an Enum typ have unique names and convertible values.
+func checkEnumValues(typ reflect.Type, values []EnumValue) error {
+	if len(values) == 0 {
+		return fmt.Errorf("%s has no enum values", typ)
+	}
+	seen := map[string]bool{}
+	for _, value := range values {
+		if value.Name == "" || strings.Contains(value.Name, ",") {
+			return fmt.Errorf("%s has invalid enum name %q", typ, value.Name)
+		}
+		if seen[value.Name] {
+			return fmt.Errorf("%s has duplicate enum name %q", typ, value.Name)
+		}
+		seen[value.Name] = true
+		if v := reflect.ValueOf(value.Value); !v.IsValid() || !v.Type().ConvertibleTo(typ) {
+			return fmt.Errorf("%s enum value %q is a %T not a %s", typ, value.Name, value.Value, typ)
+		}
+	}
+	return nil
+}
+
+// enumNames returns the names of values.
+func enumNames(values []EnumValue) []string {
+	names := make([]string, len(values))
+	for i, value := range values {
+		names[i] = value.Name
+	}
+	return names
+}
+
+// enumName returns the name of the value equal to target.
+func enumName(values []EnumValue, target reflect.Value) (string, bool) {
+	for _, value := range values {
+		v := reflect.ValueOf(value.Value)
+		if v.IsValid() && v.Type().ConvertibleTo(target.Type()) &&
+			reflect.DeepEqual(v.Convert(target.Type()).Interface(), target.Interface()) {
+			return value.Name, true
+		}
+	}
+	return "", false
+}
+
+// enumMapper decodes the name of a value of a type implementing Enum.
+type enumMapper struct{}
+
+func (enumMapper) Decode(ctx *DecodeContext, target reflect.Value) error {
+	values, ok := enumValues(target.Type())
+	if !ok {
+		return fmt.Errorf("%s does not implement kong.Enum", target.Type())
+	}
+	name, err := ctx.PopString("enum value")
+	if err != nil {
+		return err
+	}
+	fold := ctx.Value != nil && ctx.Value.Tag != nil && ctx.Value.Tag.EnumFold
+	match := -1
+	for i, value := range values {
+		if value.Name == name {
+			match = i
+			break
+		}
+		if fold && match == -1 && strings.EqualFold(value.Name, name) {
+			match = i
+		}
+	}
+	if match == -1 {
+		names := enumNames(values)
+		quoted := make([]string, len(names))
+		for i, name := range names {
+			quoted[i] = fmt.Sprintf("%q", name)
+		}
+		return findPotentialCandidates(ctx.suggestOptions(), name, names,
+			"must be one of %s but got %q", strings.Join(quoted, ","), name)
+	}
+	v := reflect.ValueOf(values[match].Value)
+	if !v.IsValid() || !v.Type().ConvertibleTo(target.Type()) {
+		return fmt.Errorf("%s enum value %q is a %T not a %s", target.Type(), name, values[match].Value, target.Type())
+	}
+	target.Set(v.Convert(target.Type()))
+	return nil
+}
+
+// enumHelp describes the values of an Enum with help, for inclusion in the help of value. Returns
+// "" if there are none.
+func enumHelp(value *Value) string {
+	_, values, ok := enumElemValues(value.Target.Type())
+	if !ok {
+		return ""
+	}
+	allowed := value.EnumMap()
+	parts := []string{}
+	for _, v := range values {
+		if v.Help != "" && (value.Enum == "" || allowed[v.Name]) {
+			parts = append(parts, v.Name+": "+strings.TrimSuffix(v.Help, "."))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "Values: " + strings.Join(parts, "; ") + "."
+}
@@ -0,0 +1,194 @@
+package kong
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ExternalCommandDescription is the JSON an external command may print when run with only the
+// --kong-describe flag, to provide its help.
+type ExternalCommandDescription struct {
+	Help   string `json:"help"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// ExternalCommandsOptions configures the discovery of external commands, see ExternalCommands.
+type ExternalCommandsOptions struct {
+	// Prefix of executables providing commands, eg. "mytool-" for "mytool-foo".
+	Prefix string
+	// Dirs to search for executables, in order. Defaults to $PATH.
+	Dirs []string
+	// Group of the commands in help. Defaults to "External commands".
+	Group string
+	// Describe runs each executable with the flag --kong-describe, expecting an
+	// ExternalCommandDescription, to provide its help. Executables are only run when their help
+	// is displayed.
+	Describe bool
+}
+
+// Apply the external commands to the model once it is built.
+func (e ExternalCommandsOptions) Apply(k *Kong) error {
+	k.postBuildOptions = append(k.postBuildOptions, OptionFunc(e.build))
+	return nil
+}
+
+// ExternalCommands adds a command for each executable in dirs (defaulting to $PATH) named prefix
+// followed by the command name, as in git and kubectl. eg. with a prefix of "mytool-", an
+// executable "mytool-foo" is run by "mytool foo ...".
+//
+// Built-in commands take precedence. All arguments after the command name are passed through
+// to the executable, along with the environment, Kong's stdout and stderr, and the exit code.
+//
+// Use ExternalCommandsOptions for further configuration.
+func ExternalCommands(prefix string, dirs ...string) Option {
+	return ExternalCommandsOptions{Prefix: prefix, Dirs: dirs}
+}
+
+func (e ExternalCommandsOptions) build(k *Kong) error {
+	group := e.Group
+	if group == "" {
+		group = "External commands"
+	}
+	dirs := e.Dirs
+	if len(dirs) == 0 {
+		dirs = filepath.SplitList(os.Getenv("PATH"))
+	}
+	builtin := map[string]bool{}
+	for _, child := range k.Model.Children {
+		builtin[child.Name] = true
+		for _, alias := range child.Aliases {
+			builtin[alias] = true
+		}
+	}
+	commands := findExternalCommands(e.Prefix, dirs)
+	for _, name := range sortedKeys(commands) {
+		if builtin[name] {
+			continue
+		}
+		if len(k.Model.Positional) > 0 {
+			return fmt.Errorf("external command %q can't be mixed with positional arguments", name)
+		}
+		path := commands[name]
+		cmd := externalCommand(name, path)
+		cmd.group = group
+		tag, err := cmd.tag()
+		if err != nil {
+			return err
+		}
+		child, err := buildCommand(k, cmd, CommandNode, map[string]bool{})
+		if err != nil {
+			return err
+		}
+		err = attachChild(k, k.Model.Node, child, k.Model.Target, reflect.StructField{Name: name}, tag, name)
+		if err != nil {
+			return err
+		}
+		if e.Describe {
+			// Executables are only run for their description when their help is displayed.
+			child.lazyHelp = func() {
+				desc := describeExternalCommand(path)
+				child.Help, child.Detail = desc.Help, desc.Detail
+			}
+		}
+	}
+	return nil
+}
+
+// externalCommand builds a passthrough command running the executable at path.
+func externalCommand(name, path string) *CommandBuilder {
+	args := []string{}
+	return NewCommand(name).
+		Tags(`passthrough:""`).
+		Arg("args", Var(&args), `optional:""`, `passthrough:""`).
+		Run(func(ctx *Context) error {
+			cmd := exec.Command(path, args...) //nolint: gosec
+			cmd.Stdin = ctx.Kong.stdin
+			cmd.Stdout = ctx.Stdout
+			cmd.Stderr = ctx.Stderr
+			// An *exec.ExitError is an ExitCoder, so the exit code is preserved by FatalIfErrorf.
+			return cmd.Run()
+		})
+}
+
+// describeExternalCommand asks the executable at path for its description, returning an empty
+// description if it does not provide one.
+func describeExternalCommand(path string) ExternalCommandDescription {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	desc := ExternalCommandDescription{}
+	out, err := exec.CommandContext(ctx, path, "--kong-describe").Output() //nolint: gosec
+	if err != nil {
+		return desc
+	}
+	_ = json.Unmarshal(out, &desc)
+	return desc
+}
+
+// findExternalCommands returns the paths of executables in dirs starting with prefix, keyed by
+// command name. Executables in earlier dirs take precedence.
+func findExternalCommands(prefix string, dirs []string) map[string]string {
+	commands := map[string]string{}
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := externalCommandName(prefix, entry.Name())
+			if !ok || commands[name] != "" {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if info, err := os.Stat(path); err != nil || !isExecutable(info) {
+				continue
+			}
+			commands[name] = path
+		}
+	}
+	return commands
+}
+
+// externalCommandName returns the command name for an executable file name.
+func externalCommandName(prefix, file string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(file))
+		found := false
+		for _, pathExt := range filepath.SplitList(strings.ToLower(os.Getenv("PATHEXT"))) {
+			found = found || (ext != "" && ext == pathExt)
+		}
+		if !found {
+			return "", false
+		}
+		file = strings.TrimSuffix(file, filepath.Ext(file))
+	}
+	name := strings.TrimPrefix(file, prefix)
+	if name == file || name == "" || strings.HasPrefix(name, "-") {
+		return "", false
+	}
+	return name, true
+}
+
+func isExecutable(info os.FileInfo) bool {
+	if info.IsDir() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode().Perm()&0o111 != 0
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,77 @@
+package kong_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/alecthomas/kong"
+)
+
+func TestExternalCommands(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	dir := t.TempDir()
+	other := t.TempDir()
+	writeScript := func(dir, name, script string, perm os.FileMode) {
+		t.Helper()
+		err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), perm)
+		assert.NoError(t, err)
+	}
+	writeScript(dir, "mytool-hello", `
+if [ "$1" = "--kong-describe" ]; then
+  touch "$0.described"
+  echo '{"help": "Say hello for $5."}'
+  exit 0
+fi
+echo "hello $* $GREETING"
+exit 3
+`, 0o755)
+	writeScript(dir, "mytool-status", "echo external status\n", 0o755)
+	writeScript(dir, "mytool-data", "", 0o644)
+	writeScript(other, "mytool-hello", "echo shadowed\n", 0o755)
+	writeScript(other, "mytool-bye", "echo bye\n", 0o755)
+
+	var cli struct {
+		Status struct{} `cmd:"" help:"Built-in status."`
+	}
+	t.Setenv("GREETING", "world")
+	w := &strings.Builder{}
+	p := mustNew(t, &cli, kong.Writers(w, w), kong.Exit(func(int) {}),
+		kong.ExternalCommandsOptions{Prefix: "mytool-", Dirs: []string{dir, other}, Group: "Plugins", Describe: true})
+
+	ctx, err := p.Parse([]string{"hello", "--name", "x", "-v"})
+	assert.NoError(t, err)
+	err = ctx.Run()
+	var exitCoder kong.ExitCoder
+	assert.True(t, errors.As(err, &exitCoder))
+	assert.Equal(t, 3, exitCoder.ExitCode())
+	assert.Equal(t, "hello --name x -v world\n", w.String())
+
+	w.Reset()
+	ctx, err = p.Parse([]string{"bye"})
+	assert.NoError(t, err)
+	assert.NoError(t, ctx.Run())
+	assert.Equal(t, "bye\n", w.String())
+
+	// Executables are only described when their help is displayed.
+	_, err = os.Stat(filepath.Join(dir, "mytool-hello.described"))
+	assert.True(t, os.IsNotExist(err))
+
+	w.Reset()
+	_, _ = p.Parse([]string{"--help"})
+	assert.Contains(t, w.String(), "status\n    Built-in status.")
+	assert.Contains(t, w.String(), "Plugins\n  bye [<args> ...]\n\n  hello [<args> ...]\n    Say hello for $5.")
+	assert.NotContains(t, w.String(), "data")
+	_, err = os.Stat(filepath.Join(dir, "mytool-hello.described"))
+	assert.NoError(t, err)
+
+	_, err = p.Parse([]string{"data"})
+	assert.Error(t, err)
+}
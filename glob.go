@@ -0,0 +1,232 @@
+package kong
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// globMapper expands glob patterns into a []string of paths.
+//
+// In addition to filepath.Match syntax, "**" matches any number of directories and a trailing
+// "/..." is equivalent to "/**". Only files are matched, and matches keep the form of the pattern,
+// eg. relative, and are deduplicated and sorted. Paths matching any of the comma separated
+// patterns in an "exclude" tag are omitted.
+//
+// If existing is true, every pattern must match at least one path. Otherwise it is only an error
+// for a required value to match nothing.
+func globMapper(existing bool) MapperFunc {
+	name := "glob"
+	if existing {
+		name = "existingglob"
+	}
+	return func(ctx *DecodeContext, target reflect.Value) error {
+		if target.Kind() != reflect.Slice || target.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("%q type must be applied to a []string not %s", name, target.Type())
+		}
+		patterns, err := popPatterns(ctx)
+		if err != nil {
+			return err
+		}
+		var excludes []string
+		for _, exclude := range ctx.Value.Tag.GetAll("exclude") {
+			excludes = append(excludes, strings.FieldsFunc(exclude, tagSplitFn)...)
+		}
+		seen := map[string]bool{}
+		matches := []string{}
+		for _, pattern := range patterns {
+			found, err := expandGlob(pattern, excludes)
+			if err != nil {
+				return err
+			}
+			if existing && len(found) == 0 && ctx.Value.Active {
+				return fmt.Errorf("no files match %q", pattern)
+			}
+			for _, match := range found {
+				if !seen[match] {
+					seen[match] = true
+					matches = append(matches, match)
+				}
+			}
+		}
+		if len(matches) == 0 && ctx.Value.Required && ctx.Value.Active {
+			return fmt.Errorf("no files match %q", strings.Join(patterns, " "))
+		}
+		sort.Strings(matches)
+		for _, match := range matches {
+			target.Set(reflect.Append(target, reflect.ValueOf(match).Convert(target.Type().Elem())))
+		}
+		return nil
+	}
+}
+
+// popPatterns pops glob patterns in the same way the slice decoder pops elements.
+func popPatterns(ctx *DecodeContext) ([]string, error) {
+	var patterns []string
+	if ctx.Value.Flag != nil {
+		t := ctx.Scan.Pop()
+		if t.IsEOL() {
+			return nil, errors.New("missing value, expecting \"<pattern>\"")
+		}
+		switch v := t.Value.(type) {
+		case string:
+			patterns = SplitEscaped(v, ctx.Value.Tag.Sep)
+		case []any:
+			for _, el := range v {
+				patterns = append(patterns, fmt.Sprint(el))
+			}
+		default:
+			patterns = []string{fmt.Sprint(v)}
+		}
+	} else {
+		for _, t := range ctx.Scan.PopWhile(func(t Token) bool { return t.IsValue() }) {
+			patterns = append(patterns, t.String())
+		}
+	}
+	out := patterns[:0]
+	for _, pattern := range patterns {
+		if pattern != "" {
+			out = append(out, pattern)
+		}
+	}
+	return out, nil
+}
+
+// expandGlob returns the files matching pattern that do not match any of excludes.
+//
+// Matches keep the form of pattern, so they are relative if pattern is relative. Directories that
+// can not be read are skipped.
+//
+// Exclusions are matched against paths relative to the first directory of pattern containing
+// wildcards. An exclusion without a "/" matches any single path element, excluding the contents
+// of matching directories.
+func expandGlob(pattern string, excludes []string) ([]string, error) {
+	if strings.HasSuffix(pattern, "/...") || pattern == "..." {
+		pattern = strings.TrimSuffix(pattern, "...") + "**"
+	}
+	if strings.HasPrefix(pattern, "~/") {
+		pattern = ExpandPath(pattern)
+	}
+	pattern = filepath.ToSlash(pattern)
+	segments := strings.Split(pattern, "/")
+	// Split into the literal root and the segments containing wildcards.
+	i := 0
+	for i < len(segments) && !hasGlobMeta(segments[i]) {
+		i++
+	}
+	if i == len(segments) {
+		if info, err := os.Stat(filepath.FromSlash(pattern)); err != nil || info.IsDir() {
+			return nil, nil //nolint: nilerr
+		}
+		return []string{filepath.FromSlash(pattern)}, nil
+	}
+	prefix := strings.Join(segments[:i], "/")
+	root := filepath.FromSlash(prefix)
+	switch {
+	case i == 0:
+		root = "."
+	case prefix == "":
+		root = string(filepath.Separator)
+	}
+	segments = segments[i:]
+	for _, exclude := range excludes {
+		if _, err := path.Match(exclude, ""); err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %w", exclude, err)
+		}
+	}
+	if _, err := path.Match(strings.Join(segments, "/"), ""); err != nil {
+		return nil, fmt.Errorf("invalid glob %q: %w", pattern, err)
+	}
+	recursive := false
+	for _, segment := range segments {
+		recursive = recursive || segment == "**"
+	}
+	var matches []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == root {
+				return filepath.SkipAll
+			}
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if p == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		elements := strings.Split(rel, "/")
+		if globExcluded(elements, excludes) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && matchGlob(segments, elements) {
+			if i > 0 {
+				rel = prefix + "/" + rel
+			}
+			matches = append(matches, filepath.FromSlash(rel))
+		}
+		if d.IsDir() && !recursive && len(elements) >= len(segments) {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return matches, err
+}
+
+func hasGlobMeta(s string) bool {
+	return strings.ContainsAny(s, `*?[\`)
+}
+
+// matchGlob matches path elements against pattern segments, where "**" matches zero or more
+// elements.
+func matchGlob(segments, elements []string) bool {
+	if len(segments) == 0 {
+		return len(elements) == 0
+	}
+	if segments[0] == "**" {
+		for i := 0; i <= len(elements); i++ {
+			if matchGlob(segments[1:], elements[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(elements) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(segments[0], elements[0]); !ok {
+		return false
+	}
+	return matchGlob(segments[1:], elements[1:])
+}
+
+func globExcluded(elements, excludes []string) bool {
+	for _, exclude := range excludes {
+		if !strings.Contains(exclude, "/") {
+			for _, element := range elements {
+				if ok, _ := path.Match(exclude, element); ok {
+					return true
+				}
+			}
+			continue
+		}
+		if matchGlob(strings.Split(exclude, "/"), elements) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package kong
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// extractGlobalFlags removes the flags tagged global:"all" from args, the arguments of a
+// passthrough command or argument, and returns the tokens to parse them with. As with other flags,
+// arguments after "--" are left untouched.
+//
+// A value that does not follow "=" is the next argument, unless the flag is a bool or counter.
+func (c *Context) extractGlobalFlags(args []string) ([]string, []Token) {
+	globals := map[string]*Flag{}
+	for _, flag := range c.Model.Flags {
+		if !flag.Tag.GlobalAll {
+			continue
+		}
+		for _, key := range c.flagKeys(flag) {
+			globals[key] = flag
+		}
+	}
+	if len(globals) == 0 {
+		return args, nil
+	}
+	rest := make([]string, 0, len(args))
+	tokens := []Token{}
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		flag, flagTokens := c.matchGlobalFlag(globals, args[i])
+		if flag == nil {
+			rest = append(rest, args[i])
+			continue
+		}
+		needsValue := len(flagTokens) == 1 && !flag.IsBool() && !flag.IsCounter()
+		if needsValue && i+1 < len(args) && args[i+1] == "--" {
+			// The value would be "--", so the flag is left with the arguments after it.
+			rest = append(rest, args[i:]...)
+			break
+		}
+		if needsValue && i+1 < len(args) {
+			i++
+			flagTokens = append(flagTokens, Token{Type: FlagValueToken, Value: args[i]})
+		}
+		tokens = append(tokens, flagTokens...)
+	}
+	return rest, tokens
+}
+
+// matchGlobalFlag returns the global flag arg refers to, if any, and the tokens of arg.
+//
+// Short flags are only matched on their own or with their value attached, not in clusters.
+func (c *Context) matchGlobalFlag(globals map[string]*Flag, arg string) (*Flag, []Token) {
+	if tokens, ok := c.flagSyntax.split(arg); ok {
+		if flag := globals[c.flagKey(tokens[0].String())]; flag != nil {
+			return flag, tokens
+		}
+	}
+	var tokens []Token
+	switch {
+	case strings.HasPrefix(arg, "--") && len(arg) > 2:
+		name, value, hasValue := strings.Cut(arg[2:], "=")
+		tokens = []Token{{Type: FlagToken, Value: name}}
+		if hasValue {
+			tokens = append(tokens, Token{Type: FlagValueToken, Value: value})
+		}
+
+	case strings.HasPrefix(arg, "-") && utf8.RuneCountInString(arg) >= 2:
+		r, size := utf8.DecodeRuneInString(arg[1:])
+		tokens = []Token{{Type: ShortFlagToken, Value: string(r)}}
+		if tail := arg[1+size:]; tail != "" {
+			if flag := globals[tokens[0].String()]; flag == nil || flag.IsBool() || flag.IsCounter() {
+				return nil, nil
+			}
+			tokens = append(tokens, Token{Type: FlagValueToken, Value: tail})
+		}
+
+	default:
+		return nil, nil
+	}
+	flag := globals[c.flagKey(tokens[0].String())]
+	if flag == nil {
+		return nil, nil
+	}
+	return flag, tokens
+}
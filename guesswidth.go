@@ -7,3 +7,7 @@ import "io"
 func guessWidth(w io.Writer) int {
 	return 80
 }
+
+func guessHeight(w io.Writer) int {
+	return 0
+}
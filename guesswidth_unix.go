@@ -20,22 +20,39 @@ func guessWidth(w io.Writer) int {
 		}
 	}
 
-	if t, ok := w.(*os.File); ok {
-		fd := t.Fd()
-		var dimensions [4]uint16
-
-		if _, _, err := syscall.Syscall6(
-			syscall.SYS_IOCTL,
-			uintptr(fd), //nolint: unconvert
-			uintptr(syscall.TIOCGWINSZ),
-			uintptr(unsafe.Pointer(&dimensions)), //nolint: gas
-			0, 0, 0,
-		); err == 0 {
-			if dimensions[1] == 0 {
-				return 80
-			}
-			return int(dimensions[1])
+	if dimensions, ok := terminalSize(w); ok {
+		if dimensions[1] == 0 {
+			return 80
 		}
+		return int(dimensions[1])
 	}
 	return 80
 }
+
+// guessHeight returns the number of rows of the terminal attached to w, or 0
+// if w is not a terminal.
+func guessHeight(w io.Writer) int {
+	if dimensions, ok := terminalSize(w); ok {
+		return int(dimensions[0])
+	}
+	return 0
+}
+
+// terminalSize returns the rows, columns, and pixel dimensions of the terminal attached to w.
+func terminalSize(w io.Writer) (dimensions [4]uint16, ok bool) {
+	t, ok := w.(*os.File)
+	if !ok {
+		return dimensions, false
+	}
+	fd := t.Fd()
+	if _, _, err := syscall.Syscall6(
+		syscall.SYS_IOCTL,
+		uintptr(fd), //nolint: unconvert
+		uintptr(syscall.TIOCGWINSZ),
+		uintptr(unsafe.Pointer(&dimensions)), //nolint: gas
+		0, 0, 0,
+	); err != 0 {
+		return dimensions, false
+	}
+	return dimensions, true
+}
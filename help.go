@@ -95,17 +95,29 @@ type HelpValueFormatter func(value *Value) string
 
 // DefaultHelpValueFormatter is the default HelpValueFormatter.
 func DefaultHelpValueFormatter(value *Value) string {
+	help := value.Help
+	if mapper, ok := value.Mapper.(structMapper); ok {
+		if fields := mapper.fieldHelp(value); fields != "" {
+			help = strings.TrimSpace(help + " " + fields)
+		}
+	}
+	if values := enumHelp(value); values != "" {
+		help = strings.TrimSpace(help + " " + values)
+	}
+	if len(value.Tag.RequiredKeys) != 0 {
+		help = strings.TrimSpace(help + " Required keys: " + strings.Join(value.Tag.RequiredKeys, ", ") + ".")
+	}
 	if len(value.Tag.Envs) == 0 || HasInterpolatedVar(value.OrigHelp, "env") {
-		return value.Help
+		return help
 	}
 	suffix := "(" + formatEnvs(value.Tag.Envs) + ")"
 	switch {
-	case strings.HasSuffix(value.Help, "."):
-		return value.Help[:len(value.Help)-1] + " " + suffix + "."
-	case value.Help == "":
+	case strings.HasSuffix(help, "."):
+		return help[:len(help)-1] + " " + suffix + "."
+	case help == "":
 		return suffix
 	default:
-		return value.Help + " " + suffix
+		return help + " " + suffix
 	}
 }
 
@@ -239,6 +251,10 @@ func printNodeDetail(w *helpWriter, node *Node, hide bool) { //nolint:gocyclo
 				}
 			}
 		}
+		if node.DefaultHelp != "" {
+			w.Print("")
+			w.Wrap(node.DefaultHelp)
+		}
 	}
 	if w.FlagsLast {
 		printFlags()
@@ -381,6 +397,8 @@ type helpWriter struct {
 	indent string
 	width  int
 	lines  *[]string
+	pager  *helpPager
+	stderr io.Writer
 	HelpOptions
 }
 
@@ -394,6 +412,8 @@ func newHelpWriter(ctx *Context, options HelpOptions) *helpWriter {
 		indent:      "",
 		width:       wrapWidth,
 		lines:       &lines,
+		pager:       ctx.pager,
+		stderr:      ctx.Stderr,
 		HelpOptions: options,
 	}
 	return w
@@ -409,14 +429,21 @@ func (h *helpWriter) Print(text string) {
 
 // Indent returns a new helpWriter indented by two characters.
 func (h *helpWriter) Indent() *helpWriter {
-	return &helpWriter{indent: h.indent + "  ", lines: h.lines, width: h.width - 2, HelpOptions: h.HelpOptions}
+	return &helpWriter{indent: h.indent + "  ", lines: h.lines, width: h.width - 2, pager: h.pager, stderr: h.stderr, HelpOptions: h.HelpOptions}
 }
 
 func (h *helpWriter) String() string {
 	return strings.Join(*h.lines, "\n")
 }
 
+// Write the help to w, through the pager if one is configured and the help does not fit on the terminal.
 func (h *helpWriter) Write(w io.Writer) error {
+	if h.pager != nil {
+		paged, err := h.pager.Page(w, h.stderr, h.String()+"\n", len(*h.lines))
+		if paged || err != nil {
+			return err
+		}
+	}
 	for _, line := range *h.lines {
 		_, err := io.WriteString(w, line+"\n")
 		if err != nil {
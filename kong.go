@@ -16,11 +16,24 @@ var (
 )
 
 func failField(parent reflect.Value, field reflect.StructField, format string, args ...any) error {
-	name := parent.Type().Name()
+	return fmt.Errorf("%s.%s: %s", structName(parent), field.Name, fmt.Sprintf(format, args...))
+}
+
+// structName returns the name of a grammar struct for use in errors.
+func structName(v reflect.Value) string {
+	if v.CanInterface() {
+		if cmd, ok := v.Interface().(builtCommand); ok {
+			if cmd.name == "" {
+				return "<anonymous command>"
+			}
+			return cmd.name
+		}
+	}
+	name := v.Type().Name()
 	if name == "" {
 		name = "<anonymous struct>"
 	}
-	return fmt.Errorf("%s.%s: %s", name, field.Name, fmt.Sprintf(format, args...))
+	return name
 }
 
 // Must creates a new Parser or panics if there is an error.
@@ -50,29 +63,52 @@ type Kong struct {
 	Stdout io.Writer
 	Stderr io.Writer
 
+	stdin        io.Reader
 	bindings     bindings
 	loader       ConfigurationLoader
 	resolvers    []Resolver
 	registry     *Registry
 	ignoreFields []*regexp.Regexp
 
-	noDefaultHelp   bool
-	allowHyphenated bool
-	usageOnError    usageOnError
-	help            HelpPrinter
-	shortHelp       HelpPrinter
-	helpFormatter   HelpValueFormatter
-	helpOptions     HelpOptions
-	helpFlag        *Flag
-	groups          []Group
-	vars            Vars
-	flagNamer       func(string) string
+	noDefaultHelp         bool
+	allowHyphenated       bool
+	allowAbbreviations    bool
+	caseInsensitive       bool
+	lazyCommands          bool
+	flagSyntax            FlagSyntax
+	noShortFlagClustering bool
+	usageOnError          usageOnError
+	help                  HelpPrinter
+	shortHelp             HelpPrinter
+	helpFormatter         HelpValueFormatter
+	helpOptions           HelpOptions
+	suggestOptions        SuggestOptions
+	helpFlag              *Flag
+	pager                 *helpPager
+	groups                []Group
+	vars                  Vars
+	flagNamer             func(string) string
 
 	// Set temporarily by Options. These are applied after build().
 	postBuildOptions []Option
 	embedded         []embedded
 	dynamicCommands  []*dynamicCommand
 
+	aliases    map[string]string
+	aliasesKey string // Configuration key of aliases, see AliasesFrom.
+
+	// Called with each lazily built command once it is built.
+	onLazyBuild []func(*Node) error
+
+	lintSeverities map[string]Severity
+
+	modelCache       ModelCache
+	recordModelCache bool
+	modelCacheKeys   map[reflect.Type]string
+
+	sequenceNode      *Node // Node at which parsing restarts after each command, if enabled.
+	sequenceSeparator string
+
 	hooks map[string][]reflect.Value
 }
 
@@ -81,15 +117,17 @@ type Kong struct {
 // See the README (https://github.com/alecthomas/kong) for usage instructions.
 func New(grammar any, options ...Option) (*Kong, error) {
 	k := &Kong{
-		Exit:          os.Exit,
-		Stdout:        os.Stdout,
-		Stderr:        os.Stderr,
-		registry:      NewRegistry().RegisterDefaults(),
-		vars:          Vars{},
-		bindings:      bindings{},
-		hooks:         make(map[string][]reflect.Value),
-		helpFormatter: DefaultHelpValueFormatter,
-		ignoreFields:  make([]*regexp.Regexp, 0),
+		Exit:           os.Exit,
+		Stdout:         os.Stdout,
+		Stderr:         os.Stderr,
+		stdin:          os.Stdin,
+		registry:       NewRegistry().RegisterDefaults(),
+		vars:           Vars{},
+		bindings:       bindings{},
+		hooks:          make(map[string][]reflect.Value),
+		helpFormatter:  DefaultHelpValueFormatter,
+		suggestOptions: DefaultSuggestOptions,
+		ignoreFields:   make([]*regexp.Regexp, 0),
 		flagNamer: func(s string) string {
 			return strings.ToLower(dashedString(s))
 		},
@@ -140,7 +178,11 @@ func New(grammar any, options ...Option) (*Kong, error) {
 
 	// Synthesise command nodes.
 	for _, dcmd := range k.dynamicCommands {
-		tag, terr := parseTagString(strings.Join(dcmd.tags, " "))
+		tags := dcmd.tags
+		if builder, ok := dcmd.cmd.(*CommandBuilder); ok {
+			tags = append(append([]string{`cmd:""`}, builder.tags...), tags...)
+		}
+		tag, terr := parseTagString(strings.Join(tags, " "))
 		if terr != nil {
 			return nil, terr
 		}
@@ -148,6 +190,17 @@ func New(grammar any, options ...Option) (*Kong, error) {
 		tag.Help = dcmd.help
 		tag.Group = dcmd.group
 		tag.Cmd = true
+		if builder, ok := dcmd.cmd.(*CommandBuilder); ok {
+			child, berr := buildCommand(k, builder, CommandNode, map[string]bool{})
+			if berr != nil {
+				return nil, berr
+			}
+			err = attachChild(k, k.Model.Node, child, k.Model.Target, reflect.StructField{Name: dcmd.name}, tag, dcmd.name)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
 		v := reflect.Indirect(reflect.ValueOf(dcmd.cmd))
 		err = buildChild(k, k.Model.Node, CommandNode, reflect.Value{}, reflect.StructField{
 			Name: dcmd.name,
@@ -158,6 +211,10 @@ func New(grammar any, options ...Option) (*Kong, error) {
 		}
 	}
 
+	if err = k.buildAliases(); err != nil {
+		return nil, err
+	}
+
 	for _, option := range k.postBuildOptions {
 		if err = option.Apply(k); err != nil {
 			return nil, err
@@ -175,9 +232,39 @@ func New(grammar any, options ...Option) (*Kong, error) {
 		return nil, err
 	}
 
+	if err = checkFoldedEnums(k.Model.Node); err != nil {
+		return nil, err
+	}
+
+	if err = checkInherit(k.Model.Node); err != nil {
+		return nil, err
+	}
+
 	return k, nil
 }
 
+// checkInherit ensures the flags inherited by commands are flags of their ancestors.
+func checkInherit(node *Node) error {
+	return Visit(node, func(visitable Visitable, next Next) error {
+		node, ok := visitable.(*Node)
+		if !ok || node.Tag == nil {
+			return next(nil)
+		}
+	INHERIT:
+		for _, name := range node.Tag.Inherit {
+			for parent := node.Parent; parent != nil; parent = parent.Parent {
+				for _, flag := range parent.Flags {
+					if flag.Name == name {
+						continue INHERIT
+					}
+				}
+			}
+			return fmt.Errorf("%s: can't inherit unknown flag --%s", commandPath(node), name)
+		}
+		return next(nil)
+	})
+}
+
 func checkOverlappingXorAnd(k *Kong) error {
 	xorGroups := map[string][]string{}
 	andGroups := map[string][]string{}
@@ -207,6 +294,25 @@ func checkOverlappingXorAnd(k *Kong) error {
 	return nil
 }
 
+// checkFoldedEnums ensures enum values matched case-insensitively do not collide.
+func checkFoldedEnums(node *Node) error {
+	return Visit(node, func(node Visitable, next Next) error {
+		value, ok := node.(*Value)
+		if !ok || !value.Tag.EnumFold || value.Enum == "" {
+			return next(nil)
+		}
+		seen := map[string]string{}
+		for _, enum := range value.EnumSlice() {
+			key := strings.ToLower(enum)
+			if other, ok := seen[key]; ok {
+				return fmt.Errorf("%s: enum values %q and %q differ only by case", value.ShortSummary(), other, enum)
+			}
+			seen[key] = enum
+		}
+		return next(nil)
+	})
+}
+
 type varStack []Vars
 
 func (v *varStack) head() Vars { return (*v)[len(*v)-1] }
@@ -482,6 +588,7 @@ func (k *Kong) FatalIfErrorf(err error, args ...any) {
 			_ = parseErr.Context.printHelp(k.helpOptions)
 			fmt.Fprintln(k.Stdout)
 		case shortUsage:
+			_ = parseErr.Context.buildHelp()
 			_ = k.shortHelp(k.helpOptions, parseErr.Context)
 			fmt.Fprintln(k.Stdout)
 		}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"testing"
@@ -1082,6 +1084,519 @@ func TestEnumMeaningfulOrder(t *testing.T) {
 	assert.EqualError(t, err, "--flag must be one of \"first\",\"second\",\"third\",\"fourth\",\"fifth\" but got \"sixth\"")
 }
 
+func TestEnumSuggestion(t *testing.T) {
+	var cli struct {
+		Flag string `enum:"debug,info,warn,error" required:""`
+	}
+	_, err := mustNew(t, &cli).Parse([]string{"--flag", "wran"})
+	assert.EqualError(t, err, `--flag must be one of "debug","info","warn","error" but got "wran", did you mean "warn"?`)
+}
+
+func TestSuggestions(t *testing.T) {
+	var cli struct {
+		Verbose bool `negatable:""`
+		Hidden  bool `hidden:""`
+
+		Build struct {
+			Target string
+		} `cmd:"" aliases:"compile"`
+		Deploy struct {
+			Force bool
+		} `cmd:""`
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		options []kong.Option
+		err     string
+	}{
+		{"Command", []string{"biuld"}, nil, `unexpected argument biuld, did you mean "build"?`},
+		{"Alias", []string{"compiel"}, nil, `unexpected argument compiel, did you mean "compile"?`},
+		{"NegatedFlag", []string{"--no-verbsoe"}, nil, `unknown flag --no-verbsoe, did you mean "--no-verbose"?`},
+		{"HiddenFlag", []string{"--hiden"}, nil, `unknown flag --hiden`},
+		{"SiblingFlag", []string{"build", "--force"}, nil, `unknown flag --force; --force is a flag of "deploy", not "build"`},
+		{"RootSiblingFlag", []string{"--target=x"}, nil, `unknown flag --target; --target is a flag of "build"`},
+		{"Disabled", []string{"biuld"}, []kong.Option{kong.SuggestOptions{}}, `unexpected argument biuld`},
+		{"PrefixOnly", []string{"dep"}, []kong.Option{kong.SuggestOptions{Prefix: true}}, `unexpected argument dep, did you mean "deploy"?`},
+		{"Limit", []string{"--verbos"}, []kong.Option{kong.SuggestOptions{MaxDistance: 2, Prefix: true, Limit: 1}}, `unknown flag --verbos, did you mean "--verbose"?`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := mustNew(t, &cli, test.options...).Parse(test.args)
+			assert.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	suggest := kong.DefaultSuggestOptions
+	assert.Equal(t, []string{"install", "instance"}, suggest.Suggest("inst", []string{"instance", "install", "list"}))
+	assert.Equal(t, []string{"list"}, suggest.Suggest("lsit", []string{"install", "list"}))
+	assert.Equal(t, []string{}, suggest.Suggest("x", []string{"a", "b"}))
+	assert.Equal(t, []string{"test"}, suggest.Suggest("tst", []string{"test", "lint"}))
+	assert.Equal(t, []string{}, suggest.Suggest("-x", []string{"-h", "-v"}))
+}
+
+func TestSuggestMapKeys(t *testing.T) {
+	var cli struct {
+		Env map[string]string `requiredkeys:"region"`
+	}
+	_, err := mustNew(t, &cli).Parse([]string{"--env=regoin=eu"})
+	assert.EqualError(t, err, `--env: missing required key "region", is "regoin" misspelt?`)
+
+	_, err = mustNew(t, &cli, kong.SuggestOptions{}).Parse([]string{"--env=regoin=eu"})
+	assert.EqualError(t, err, `--env: missing required key "region"`)
+}
+
+func TestAllowAbbreviations(t *testing.T) {
+	type cli struct {
+		Verbose bool   `negatable:"" default:"true"`
+		Version bool   `aliases:"ver-info"`
+		Output  string `aliases:"out"`
+		Secret  bool   `hidden:""`
+
+		Install struct {
+			Arg string `arg:"" optional:""`
+		} `cmd:"" aliases:"add"`
+		Inspect struct{} `cmd:""`
+		Debug   struct{} `cmd:"" hidden:""`
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		command string
+		err     string
+	}{
+		{"Flag", []string{"inspect", "--verb"}, "inspect", ""},
+		{"NegatedFlag", []string{"inspect", "--no-verb"}, "inspect", ""},
+		{"AliasPrefixSameFlag", []string{"inspect", "--outp=x"}, "inspect", ""},
+		{"ExactAlias", []string{"inspect", "--out=x"}, "inspect", ""},
+		{"AmbiguousFlag", []string{"inspect", "--ver"}, "", `ambiguous flag --ver, could be one of "--ver-info", "--verbose", "--version"`},
+		{"HiddenFlag", []string{"inspect", "--sec"}, "", `unknown flag --sec`},
+		{"Command", []string{"insta", "x"}, "install <arg>", ""},
+		{"CommandAlias", []string{"ad", "x"}, "install <arg>", ""},
+		{"AmbiguousCommand", []string{"ins"}, "", `ambiguous command ins, could be one of "inspect", "install"`},
+		{"HiddenCommand", []string{"deb"}, "", `unexpected argument deb`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var actual cli
+			ctx, err := mustNew(t, &actual, kong.AllowAbbreviations()).Parse(test.args)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.command, ctx.Command())
+		})
+	}
+
+	var actual cli
+	_, err := mustNew(t, &actual, kong.AllowAbbreviations()).Parse([]string{"--no-verb", "--outp=x", "inspect"})
+	assert.NoError(t, err)
+	assert.False(t, actual.Verbose)
+	assert.Equal(t, "x", actual.Output)
+
+	t.Run("DisabledByDefault", func(t *testing.T) {
+		var actual cli
+		_, err := mustNew(t, &actual).Parse([]string{"inspect", "--verb"})
+		assert.Error(t, err)
+	})
+}
+
+func TestCaseInsensitive(t *testing.T) {
+	var cli struct {
+		Output  string `aliases:"out-file"`
+		Verbose bool   `negatable:"" default:"true"`
+		Debug   bool   `short:"d"`
+
+		Deploy struct {
+			Force bool
+		} `cmd:"" aliases:"ship"`
+	}
+	p := mustNew(t, &cli, kong.CaseInsensitive())
+	ctx, err := p.Parse([]string{"--OUTPUT=x", "--No-Verbose", "Deploy", "--Force"})
+	assert.NoError(t, err)
+	assert.Equal(t, "deploy", ctx.Command())
+	assert.Equal(t, "x", cli.Output)
+	assert.False(t, cli.Verbose)
+	assert.True(t, cli.Deploy.Force)
+
+	ctx, err = p.Parse([]string{"--Out-File=y", "SHIP"})
+	assert.NoError(t, err)
+	assert.Equal(t, "deploy", ctx.Command())
+	assert.Equal(t, "y", cli.Output)
+
+	_, err = p.Parse([]string{"-D", "deploy"})
+	assert.EqualError(t, err, "unknown flag -D")
+
+	_, err = mustNew(t, &cli).Parse([]string{"Deploy"})
+	assert.Error(t, err)
+}
+
+func TestCaseInsensitiveDuplicates(t *testing.T) {
+	var flags struct {
+		Output string
+		OUTPUT string `name:"OUTPUT"`
+	}
+	_, err := kong.New(&flags)
+	assert.NoError(t, err)
+	_, err = kong.New(&flags, kong.CaseInsensitive())
+	assert.EqualError(t, err, "<anonymous struct>.OUTPUT: duplicate flag --OUTPUT")
+
+	var cmds struct {
+		Deploy struct{} `cmd:""`
+		DEPLOY struct{} `cmd:"" name:"DEPLOY"`
+	}
+	_, err = kong.New(&cmds, kong.CaseInsensitive())
+	assert.EqualError(t, err, `duplicate command name "DEPLOY" in command "<anonymous struct>"`)
+}
+
+func TestEnumFold(t *testing.T) {
+	var cli struct {
+		Level string `enum:"debug,info" enumfold:"" default:"info"`
+		Other string `enum:"debug,info" default:"info"`
+	}
+	p := mustNew(t, &cli)
+	_, err := p.Parse([]string{"--level=DEBUG"})
+	assert.NoError(t, err)
+	assert.Equal(t, "debug", cli.Level)
+
+	_, err = p.Parse([]string{"--other=DEBUG"})
+	assert.EqualError(t, err, `--other must be one of "debug","info" but got "DEBUG"`)
+
+	var collision struct {
+		Level string `enum:"a,A" enumfold:"" default:"a"`
+	}
+	_, err = kong.New(&collision)
+	assert.EqualError(t, err, `--level: enum values "a" and "A" differ only by case`)
+}
+
+func TestFlagSyntax(t *testing.T) {
+	type cli struct {
+		Output  string   `short:"o"`
+		Verbose bool     `short:"v"`
+		Debug   bool     `short:"d"`
+		Files   []string `arg:"" optional:""`
+	}
+	t.Run("Go", func(t *testing.T) {
+		var actual cli
+		_, err := mustNew(t, &actual, kong.WithFlagSyntax(kong.GoFlagSyntax)).Parse([]string{"-output=x", "-verbose", "-d", "--", "-file"})
+		assert.NoError(t, err)
+		assert.Equal(t, cli{Output: "x", Verbose: true, Debug: true, Files: []string{"-file"}}, actual)
+	})
+	t.Run("GoSeparateValue", func(t *testing.T) {
+		var actual cli
+		_, err := mustNew(t, &actual, kong.WithFlagSyntax(kong.GoFlagSyntax)).Parse([]string{"-output", "x", "-o=y"})
+		assert.NoError(t, err)
+		assert.Equal(t, "y", actual.Output)
+	})
+	t.Run("Windows", func(t *testing.T) {
+		var actual cli
+		_, err := mustNew(t, &actual, kong.WithFlagSyntax(kong.WindowsFlagSyntax)).Parse([]string{"/output:C:/tmp", "/v", "--debug", "/usr/share"})
+		assert.NoError(t, err)
+		assert.Equal(t, cli{Output: "C:/tmp", Verbose: true, Debug: true, Files: []string{"/usr/share"}}, actual)
+	})
+	t.Run("WindowsUnknownFlag", func(t *testing.T) {
+		var actual cli
+		_, err := mustNew(t, &actual, kong.WithFlagSyntax(kong.WindowsFlagSyntax)).Parse([]string{"/v", "/d", "/tmp"})
+		assert.NoError(t, err)
+		assert.Equal(t, cli{Verbose: true, Debug: true, Files: []string{"/tmp"}}, actual)
+	})
+	t.Run("GoShortFlags", func(t *testing.T) {
+		var actual cli
+		_, err := mustNew(t, &actual, kong.WithFlagSyntax(kong.GoFlagSyntax)).Parse([]string{"-ofile", "-vd"})
+		assert.NoError(t, err)
+		assert.Equal(t, cli{Output: "file", Verbose: true, Debug: true}, actual)
+	})
+	t.Run("GoUnknownFlag", func(t *testing.T) {
+		var actual cli
+		_, err := mustNew(t, &actual, kong.WithFlagSyntax(kong.GoFlagSyntax)).Parse([]string{"-force"})
+		assert.EqualError(t, err, "unknown flag -f")
+	})
+	t.Run("GNUDefault", func(t *testing.T) {
+		var actual cli
+		_, err := mustNew(t, &actual).Parse([]string{"/output:x"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"/output:x"}, actual.Files)
+	})
+}
+
+func TestShortFlagClustering(t *testing.T) {
+	type cli struct {
+		Output  string `short:"o"`
+		Verbose bool   `short:"v"`
+		Debug   bool   `short:"d"`
+	}
+	var actual cli
+	_, err := mustNew(t, &actual).Parse([]string{"-vd"})
+	assert.NoError(t, err)
+	assert.Equal(t, cli{Verbose: true, Debug: true}, actual)
+
+	actual = cli{}
+	p := mustNew(t, &actual, kong.WithShortFlagClustering(false))
+	_, err = p.Parse([]string{"-vd"})
+	assert.EqualError(t, err, `unexpected "d" after short flag, short flags can not be combined`)
+
+	_, err = p.Parse([]string{"-ofile", "-v"})
+	assert.NoError(t, err)
+	assert.Equal(t, cli{Output: "file", Verbose: true}, actual)
+}
+
+func TestFromFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "token")
+	assert.NoError(t, os.WriteFile(path, []byte("secret\n"), 0o600))
+
+	type cli struct {
+		Token  string   `fromfile:"" env:"TEST_TOKEN"`
+		Count  int      `fromfile:""`
+		Names  []string `fromfile:""`
+		Plain  string
+		Config string `fromfile:""`
+	}
+	t.Run("File", func(t *testing.T) {
+		var actual cli
+		_, err := mustNew(t, &actual).Parse([]string{"--token", "@" + path, "--plain=@" + path})
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", actual.Token)
+		assert.Equal(t, "@"+path, actual.Plain)
+	})
+	t.Run("Stdin", func(t *testing.T) {
+		var actual cli
+		_, err := mustNew(t, &actual, kong.Stdin(strings.NewReader("42\n"))).Parse([]string{"--count=-"})
+		assert.NoError(t, err)
+		assert.Equal(t, 42, actual.Count)
+
+		_, err = mustNew(t, &actual, kong.Stdin(strings.NewReader("a,b"))).Parse([]string{"--names", "-"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, actual.Names)
+		var secret string
+		app := kong.NewCommand("").Command(kong.NewCommand("login").Flag("secret", kong.Var(&secret), `fromfile:""`))
+		_, err = mustNew(t, app, kong.Stdin(strings.NewReader("s3cret\n"))).Parse([]string{"login", "--secret=-"})
+		assert.NoError(t, err)
+		assert.Equal(t, "s3cret", secret)
+	})
+	t.Run("Escape", func(t *testing.T) {
+		var actual cli
+		_, err := mustNew(t, &actual).Parse([]string{"--token=@@literal"})
+		assert.NoError(t, err)
+		assert.Equal(t, "@literal", actual.Token)
+	})
+	t.Run("Env", func(t *testing.T) {
+		t.Setenv("TEST_TOKEN", "@"+path)
+		var actual cli
+		_, err := mustNew(t, &actual).Parse(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", actual.Token)
+	})
+	t.Run("Resolver", func(t *testing.T) {
+		resolver, err := kong.JSON(strings.NewReader(`{"config": "@` + path + `"}`))
+		assert.NoError(t, err)
+		var actual cli
+		_, err = mustNew(t, &actual, kong.Resolvers(resolver)).Parse(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", actual.Config)
+	})
+	t.Run("Missing", func(t *testing.T) {
+		var actual cli
+		_, err := mustNew(t, &actual).Parse([]string{"--token=@" + filepath.Join(dir, "missing")})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "--token: open ")
+	})
+}
+
+type structFlagDB struct {
+	Host  string `required:"" help:"Database host."`
+	Port  int    `default:"5432"`
+	TLS   bool   `name:"tls"`
+	Mode  string `enum:"ro,rw" default:"rw"`
+	Debug bool   `kong:"-"`
+}
+
+func TestStructFlag(t *testing.T) {
+	var cli struct {
+		DB      structFlagDB  `type:"struct" help:"Database connection."`
+		Replica *structFlagDB `type:"struct" sep:";"`
+	}
+	p := mustNew(t, &cli)
+	_, err := p.Parse([]string{"--db", "host=x,port=6543,tls", "--replica=host=y;mode=ro"})
+	assert.NoError(t, err)
+	assert.Equal(t, structFlagDB{Host: "x", Port: 6543, TLS: true, Mode: "rw"}, cli.DB)
+	assert.Equal(t, &structFlagDB{Host: "y", Port: 5432, Mode: "ro"}, cli.Replica)
+
+	_, err = p.Parse([]string{"--db", "port=1"})
+	assert.EqualError(t, err, `--db: missing required field "host"`)
+
+	_, err = p.Parse([]string{"--db", "host=x,prot=1"})
+	assert.EqualError(t, err, `--db: unknown field "prot", did you mean "port"?`)
+
+	_, err = p.Parse([]string{"--db", "host=x,port=abc"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "--db: port: ")
+
+	_, err = p.Parse([]string{"--db", "host=x,mode=rx"})
+	assert.EqualError(t, err, `--db: mode: must be one of ro,rw but got "rx"`)
+
+	_, err = p.Parse([]string{"--db", "host"})
+	assert.EqualError(t, err, `--db: expected host=<value>`)
+}
+
+func TestStructFlagOptIn(t *testing.T) {
+	var cli struct {
+		DB structFlagDB
+	}
+	_, err := kong.New(&cli)
+	assert.EqualError(t, err, `<anonymous struct>.DB: unsupported field type kong_test.structFlagDB, perhaps missing a cmd:"" tag?`)
+
+	var scalar struct {
+		Port int `type:"struct"`
+	}
+	_, err = kong.New(&scalar)
+	assert.EqualError(t, err, "<anonymous struct>.Port: expected a struct but got int")
+}
+
+func TestStructFlagSuggestOptions(t *testing.T) {
+	var cli struct {
+		DB structFlagDB `type:"struct"`
+	}
+	_, err := mustNew(t, &cli, kong.SuggestOptions{}).Parse([]string{"--db", "host=x,prot=1"})
+	assert.EqualError(t, err, `--db: unknown field "prot"`)
+}
+
+func TestStructFlagResolver(t *testing.T) {
+	var cli struct {
+		DB structFlagDB `type:"struct"`
+	}
+	resolver, err := kong.JSON(strings.NewReader(`{"db": {"host": "x", "port": 1, "tls": true}}`))
+	assert.NoError(t, err)
+	_, err = mustNew(t, &cli, kong.Resolvers(resolver)).Parse(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, structFlagDB{Host: "x", Port: 1, TLS: true, Mode: "rw"}, cli.DB)
+}
+
+func TestStructFlagHelp(t *testing.T) {
+	var cli struct {
+		DB structFlagDB `type:"struct" help:"Database connection."`
+	}
+	w := &strings.Builder{}
+	p := mustNew(t, &cli, kong.Writers(w, w), kong.Exit(func(int) {}))
+	_, _ = p.Parse([]string{"--help"})
+	assert.Contains(t, w.String(), "--db=host=HOST,[port=5432],[tls],[mode=rw]")
+	assert.Contains(t, w.String(), "Database connection. Fields: host: Database host.")
+}
+
+func TestStructFlagInvalid(t *testing.T) {
+	var cli struct {
+		DB struct {
+			Cmd struct{} `cmd:""`
+		}
+	}
+	_, err := kong.New(&cli)
+	assert.Error(t, err)
+}
+
+type enumLevel int
+
+const (
+	enumLevelDebug enumLevel = iota
+	enumLevelInfo
+	enumLevelError
+)
+
+func (enumLevel) Values() []kong.EnumValue {
+	return []kong.EnumValue{
+		{Name: "debug", Help: "Verbose logging.", Value: enumLevelDebug},
+		{Name: "info", Help: "Normal logging.", Value: enumLevelInfo},
+		{Name: "error", Value: enumLevelError},
+	}
+}
+
+func TestEnumInterface(t *testing.T) {
+	var cli struct {
+		Level  enumLevel   `default:"info" help:"Log level, one of ${enum}."`
+		Levels []enumLevel `enumfold:""`
+		Ptr    *enumLevel
+		Subset enumLevel `enum:"info,error" default:"error"`
+	}
+	p := mustNew(t, &cli)
+	_, err := p.Parse([]string{"--level=debug", "--levels=INFO,error", "--ptr=error"})
+	assert.NoError(t, err)
+	assert.Equal(t, enumLevelDebug, cli.Level)
+	assert.Equal(t, []enumLevel{enumLevelInfo, enumLevelError}, cli.Levels)
+	assert.Equal(t, enumLevelError, *cli.Ptr)
+	assert.Equal(t, enumLevelError, cli.Subset)
+
+	_, err = p.Parse(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, enumLevelInfo, cli.Level)
+
+	_, err = p.Parse([]string{"--level=debgu"})
+	assert.EqualError(t, err, `--level: must be one of "debug","info","error" but got "debgu", did you mean "debug"?`)
+
+	_, err = p.Parse([]string{"--subset=debug"})
+	assert.EqualError(t, err, `--subset must be one of "info","error" but got "debug"`)
+
+	w := &strings.Builder{}
+	p = mustNew(t, &cli, kong.Writers(w, w), kong.Exit(func(int) {}))
+	_, _ = p.Parse([]string{"--help"})
+	assert.Contains(t, w.String(), "--level=info      Log level, one of debug,info,error. Values: debug:\n")
+	assert.Contains(t, w.String(), "--subset=error    Values: info: Normal logging.\n")
+}
+
+type enumColour int
+
+func (enumColour) Values() []kong.EnumValue {
+	return []kong.EnumValue{{Name: "red", Value: enumColour(1)}, {Name: "green", Value: enumColour(2)}}
+}
+
+func TestEnumInterfaceZero(t *testing.T) {
+	// The zero value is not a colour, so an optional flag without a default is rejected.
+	var optional struct {
+		Colour enumColour
+	}
+	_, err := kong.New(&optional)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "enum kong_test.enumColour is only valid if it is either required")
+
+	var cli struct {
+		Colour  enumColour `required:""`
+		Default enumColour `default:"red"`
+		Pointer *enumColour
+	}
+	p := mustNew(t, &cli)
+	_, err = p.Parse(nil)
+	assert.EqualError(t, err, "missing flags: --colour=ENUM-COLOUR")
+
+	_, err = p.Parse([]string{"--colour=green"})
+	assert.NoError(t, err)
+	assert.Equal(t, enumColour(2), cli.Colour)
+	assert.Equal(t, enumColour(1), cli.Default)
+	assert.Zero(t, cli.Pointer)
+}
+
+func TestEnumInterfaceSuggestOptions(t *testing.T) {
+	var cli struct {
+		Level enumLevel
+	}
+	_, err := mustNew(t, &cli, kong.SuggestOptions{}).Parse([]string{"--level=debgu"})
+	assert.EqualError(t, err, `--level: must be one of "debug","info","error" but got "debgu"`)
+}
+
+type enumInvalid string
+
+func (enumInvalid) Values() []kong.EnumValue {
+	return []kong.EnumValue{{Name: "a", Value: "a"}, {Name: "a", Value: "b"}}
+}
+
+func TestEnumInterfaceInvalid(t *testing.T) {
+	var cli struct {
+		Flag enumInvalid
+	}
+	_, err := kong.New(&cli)
+	assert.EqualError(t, err, `<anonymous struct>.Flag: kong_test.enumInvalid has duplicate enum name "a"`)
+}
+
 type commandWithHook struct {
 	value string
 }
@@ -1353,6 +1868,61 @@ func TestEnumSequence(t *testing.T) {
 	assert.Equal(t, []string{"a"}, cli.State)
 }
 
+func TestEnumSliceModifiers(t *testing.T) {
+	var cli struct {
+		Features []string    `enum:"a,b,c" unique:"" sorted:"" maxitems:"3"`
+		Levels   []enumLevel `sorted:"" unique:"" minitems:"2"`
+		Ints     []int       `sorted:""`
+		Args     []string    `arg:"" optional:"" enum:"x,y"`
+	}
+	p := mustNew(t, &cli)
+	_, err := p.Parse([]string{"--features=c,a", "--features=c,b", "--levels=error,debug,error", "--ints=10,-1,2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, cli.Features)
+	assert.Equal(t, []enumLevel{enumLevelDebug, enumLevelError}, cli.Levels)
+	assert.Equal(t, []int{-1, 2, 10}, cli.Ints)
+
+	p = mustNew(t, &cli)
+	_, err = p.Parse([]string{"--features=all", "all"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, cli.Features)
+	assert.Equal(t, []string{"x", "y"}, cli.Args)
+
+	p = mustNew(t, &cli)
+	_, err = p.Parse([]string{"--features=a", "--features=none,b", "--levels=all"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b"}, cli.Features)
+	assert.Equal(t, []enumLevel{enumLevelDebug, enumLevelInfo, enumLevelError}, cli.Levels)
+
+	p = mustNew(t, &cli)
+	_, err = p.Parse([]string{"--levels=info"})
+	assert.EqualError(t, err, "--levels: expected at least 2 values but got 1")
+
+	p = mustNew(t, &cli)
+	_, err = p.Parse([]string{"--features=a,b,a", "--features=d"})
+	assert.EqualError(t, err, `--features must be one of "a","b","c" but got "d"`)
+
+	var maxCLI struct {
+		Features []string `enum:"a,b,c" maxitems:"2"`
+	}
+	p = mustNew(t, &maxCLI)
+	_, err = p.Parse([]string{"--features=all"})
+	assert.EqualError(t, err, "--features: expected at most 2 values but got 3")
+}
+
+func TestEnumSliceModifiersInvalid(t *testing.T) {
+	var cli struct {
+		Flag string `unique:""`
+	}
+	_, err := kong.New(&cli)
+	assert.EqualError(t, err, "<anonymous struct>.Flag: unique, sorted, minitems and maxitems can only be applied to slices")
+	var minmax struct {
+		Flag []string `minitems:"3" maxitems:"2"`
+	}
+	_, err = kong.New(&minmax)
+	assert.EqualError(t, err, "<anonymous struct>.Flag: minitems 3 is greater than maxitems 2")
+}
+
 func TestIssue40EnumAcrossCommands(t *testing.T) {
 	var cli struct {
 		One struct {
@@ -1548,7 +2118,7 @@ func TestDefaultCommandPrecedence(t *testing.T) {
 
 	// A flag on a default command should not be valid on a sibling command
 	_, err = p.Parse([]string{"one", "--flag"})
-	assert.EqualError(t, err, "unknown flag --flag")
+	assert.EqualError(t, err, `unknown flag --flag; --flag is a flag of "two", not "one"`)
 }
 
 func TestLoneHpyhen(t *testing.T) {
@@ -2924,3 +3494,365 @@ func TestParseHyphenParameter(t *testing.T) {
 		assert.Equal(t, &shortFlag{Numeric: -10}, actual)
 	})
 }
+
+func TestLazyCommands(t *testing.T) {
+	var cli struct {
+		Debug bool `short:"d"`
+		Serve struct {
+			Port int    `default:"8080" help:"Port (default ${default})."`
+			Dir  string `arg:"" optional:"" default:"${dir}"`
+		} `cmd:"" help:"Serve ${what}." aliases:"s"`
+		Broken struct {
+			Debug bool
+		} `cmd:"" help:"Broken."`
+	}
+	w := &strings.Builder{}
+	p := mustNew(t, &cli, kong.LazyCommands(), kong.Vars{"dir": "/srv", "what": "files"},
+		kong.Writers(w, w), kong.Exit(func(int) {}))
+	serve := p.Model.Children[0]
+	assert.Equal(t, "Serve files.", serve.Help)
+	assert.Equal(t, []string{"s"}, serve.Aliases)
+	assert.Equal(t, 0, len(serve.Flags))
+
+	// Unknown flags are not looked up in commands that have not been built.
+	_, err := p.Parse([]string{"--port=1"})
+	assert.EqualError(t, err, "unknown flag --port")
+	assert.Equal(t, 0, len(serve.Flags))
+
+	ctx, err := p.Parse([]string{"-d", "s"})
+	assert.NoError(t, err)
+	assert.Equal(t, "serve", ctx.Command())
+	assert.True(t, cli.Debug)
+	assert.Equal(t, 8080, cli.Serve.Port)
+	assert.Equal(t, "/srv", cli.Serve.Dir)
+	assert.Equal(t, "Port (default 8080).", serve.Flags[0].Help)
+	assert.Equal(t, 0, len(p.Model.Children[1].Flags))
+
+	// Errors in the grammar of a command are reported when it is built.
+	_, err = p.Parse([]string{"broken"})
+	assert.EqualError(t, err, "broken: <anonymous struct>.Debug: duplicate flag --debug")
+	assert.EqualError(t, p.Model.Node.Build(), "broken: <anonymous struct>.Debug: duplicate flag --debug")
+}
+
+func TestLazyCommandsHelp(t *testing.T) {
+	var cli struct {
+		Serve struct {
+			Port int `help:"Port." env:"PORT"`
+		} `cmd:"" help:"Serve files."`
+	}
+	w := &strings.Builder{}
+	p := mustNew(t, &cli, kong.LazyCommands(), kong.DefaultEnvars("APP"), kong.Writers(w, w), kong.Exit(func(int) {}))
+	_, _ = p.Parse([]string{"--help"})
+	assert.Contains(t, w.String(), "serve [flags]\n    Serve files.")
+	assert.Equal(t, []string{"PORT"}, p.Model.Children[0].Flags[0].Envs)
+}
+
+func TestDynamicDefaultCommand(t *testing.T) {
+	type cli struct {
+		Open struct {
+			File string `arg:"" type:"existingfile"`
+		} `cmd:"" help:"Open a file."`
+		List struct {
+			All bool
+		} `cmd:"" help:"List files."`
+		Remote struct {
+			Add struct{} `cmd:""`
+		} `cmd:""`
+	}
+	file := filepath.Join(t.TempDir(), "file.txt")
+	assert.NoError(t, os.WriteFile(file, nil, 0o600))
+	selectDefault := func(ctx *kong.Context, node *kong.Node, args []string) (*kong.Node, error) {
+		if len(args) == 0 {
+			return node.Children[1], nil
+		}
+		if _, err := os.Stat(args[0]); err == nil {
+			return node.Children[0], nil
+		}
+		return nil, nil
+	}
+	parse := func(args ...string) (*cli, *kong.Context, error) {
+		t.Helper()
+		var c cli
+		p := mustNew(t, &c, kong.DynamicDefaultCommand("", `If the first argument is a file, "open" is run, otherwise "list".`, selectDefault))
+		ctx, err := p.Parse(args)
+		return &c, ctx, err
+	}
+
+	c, ctx, err := parse(file)
+	assert.NoError(t, err)
+	assert.Equal(t, "open <file>", ctx.Command())
+	assert.Equal(t, file, c.Open.File)
+
+	_, ctx, err = parse()
+	assert.NoError(t, err)
+	assert.Equal(t, "list", ctx.Command())
+
+	_, ctx, err = parse("list", "--all")
+	assert.NoError(t, err)
+	assert.Equal(t, "list", ctx.Command())
+
+	_, _, err = parse("missing.txt")
+	assert.EqualError(t, err, `unexpected argument missing.txt`)
+
+	var c2 cli
+	w := &strings.Builder{}
+	p := mustNew(t, &c2, kong.Writers(w, w), kong.Exit(func(int) {}),
+		kong.DynamicDefaultCommand("", `If the first argument is a file, "open" is run, otherwise "list".`, selectDefault))
+	_, _ = p.Parse([]string{"--help"})
+	assert.Contains(t, w.String(), "  remote add\n\nIf the first argument is a file, \"open\" is run, otherwise \"list\".\n")
+
+	p = mustNew(t, &c2, kong.DynamicDefaultCommand("remote", "", func(ctx *kong.Context, node *kong.Node, args []string) (*kong.Node, error) {
+		return node.Parent.Children[0], nil
+	}))
+	_, err = p.Parse([]string{"remote"})
+	assert.EqualError(t, err, `default command "open" is not a sub-command of "remote"`)
+
+	_, err = kong.New(&c2, kong.DynamicDefaultCommand("missing", "", selectDefault))
+	assert.EqualError(t, err, `DynamicDefaultCommand: unknown command "missing"`)
+}
+
+type sequenceCmd struct {
+	Name string    `arg:"" optional:""`
+	Fast bool      `help:"Go fast."`
+	log  *[]string `kong:"-"`
+}
+
+func (s *sequenceCmd) Run(ctx *kong.Context) error {
+	*s.log = append(*s.log, fmt.Sprintf("%s %s %v", ctx.Command(), s.Name, s.Fast))
+	if s.Name == "fail" {
+		return fmt.Errorf("failed")
+	}
+	return nil
+}
+
+func TestCommandSequence(t *testing.T) {
+	type cli struct {
+		Verbose bool `short:"v"`
+		Build   struct {
+			Target string `arg:""`
+			Race   bool
+		} `cmd:""`
+		Test struct {
+			Short bool
+		} `cmd:""`
+		Lint struct{} `cmd:""`
+		Run  struct {
+			Args []string `arg:"" optional:""`
+		} `cmd:""`
+	}
+	parse := func(args ...string) (*cli, *kong.Context, error) {
+		t.Helper()
+		var c cli
+		p := mustNew(t, &c, kong.CommandSequence("+"))
+		ctx, err := p.Parse(args)
+		return &c, ctx, err
+	}
+
+	c, ctx, err := parse("build", "app", "--race", "test", "--short", "-v", "lint")
+	assert.NoError(t, err)
+	commands := []string{}
+	for _, command := range ctx.Sequence() {
+		commands = append(commands, command.Command())
+	}
+	assert.Equal(t, []string{"build <target>", "test", "lint"}, commands)
+	assert.Equal(t, "app", c.Build.Target)
+	assert.True(t, c.Build.Race)
+	assert.True(t, c.Test.Short)
+	assert.True(t, c.Verbose)
+
+	c, ctx, err = parse("run", "a", "b", "+", "test", "+")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, c.Run.Args)
+	assert.Equal(t, 2, len(ctx.Sequence()))
+
+	_, ctx, err = parse("lint")
+	assert.NoError(t, err)
+	assert.Equal(t, []*kong.Context{ctx}, ctx.Sequence())
+
+	// Flags are scoped to their command.
+	_, _, err = parse("test", "build", "app", "--short")
+	assert.EqualError(t, err, `unknown flag --short; --short is a flag of "test", not "build"`)
+
+	// Each command is validated.
+	_, _, err = parse("test", "build")
+	assert.EqualError(t, err, `expected "<target>"`)
+
+	_, err = kong.New(&cli{}, kong.CommandSequenceOptions{Path: "lint"})
+	assert.EqualError(t, err, `CommandSequence: "lint" has no sub-commands`)
+}
+
+func TestCommandSequenceInheritedFlags(t *testing.T) {
+	type cli struct {
+		Token  string `required:""`
+		Deploy struct {
+			Env string `required:""`
+		} `cmd:""`
+		Clean struct {
+			Force bool
+		} `cmd:"" inherit:"-"`
+	}
+	parse := func(args ...string) error {
+		t.Helper()
+		_, err := mustNew(t, &cli{}, kong.CommandSequence("")).Parse(args)
+		return err
+	}
+
+	assert.NoError(t, parse("--token=t", "deploy", "--env=prod", "clean", "--force"))
+
+	// Required flags are checked for each command that inherits them, wherever it is in the sequence.
+	assert.NoError(t, parse("clean", "--force"))
+	assert.EqualError(t, parse("deploy", "--env=prod", "clean"), "missing flags: --token=STRING")
+	assert.EqualError(t, parse("--token=t", "clean", "deploy"), "missing flags: --env=STRING")
+}
+
+func TestCommandSequenceRun(t *testing.T) {
+	log := []string{}
+	var cli struct {
+		Tasks struct {
+			A sequenceCmd `cmd:""`
+			B sequenceCmd `cmd:""`
+		} `cmd:""`
+	}
+	cli.Tasks.A.log = &log
+	cli.Tasks.B.log = &log
+	p := mustNew(t, &cli, kong.CommandSequenceOptions{Path: "tasks", Separator: ";"})
+
+	ctx, err := p.Parse([]string{"tasks", "a", "x", "b", "--fast"})
+	assert.NoError(t, err)
+	assert.NoError(t, ctx.Run())
+	assert.Equal(t, []string{"tasks a <name> x false", "tasks b  true"}, log)
+
+	// Commands share their values, so may not be repeated.
+	_, err = p.Parse([]string{"tasks", "a", "x", ";", "a", "y"})
+	assert.EqualError(t, err, `command "a" can only be given once`)
+
+	log = log[:0]
+	ctx, err = p.Parse([]string{"tasks", "a", "fail", "b"})
+	assert.NoError(t, err)
+	assert.EqualError(t, ctx.Run(), "failed")
+	assert.Equal(t, []string{"tasks a <name> fail false"}, log)
+}
+
+func TestGlobalFlags(t *testing.T) {
+	type cli struct {
+		Verbose int    `short:"v" type:"counter" global:""`
+		Log     string `global:"all"`
+		Trace   bool   `short:"t" global:"all"`
+		Debug   bool
+		Pattern string
+		Exec    struct {
+			Args []string `arg:"" optional:""`
+		} `cmd:"" inherit:"-"`
+		Run struct {
+			Args []string `arg:"" passthrough:""`
+		} `cmd:"" passthrough:""`
+	}
+	parse := func(args ...string) (*cli, error) {
+		t.Helper()
+		var c cli
+		p := mustNew(t, &c, kong.WithHyphenPrefixedParameters(true))
+		_, err := p.Parse(args)
+		return &c, err
+	}
+
+	// Global flags are recognised by commands that do not inherit flags, but not after "--".
+	c, err := parse("--verbose", "exec", "-v", "-t", "--", "cmd", "--verbose", "--debug", "--log", "info", "-t")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, c.Verbose)
+	assert.Equal(t, "", c.Log)
+	assert.True(t, c.Trace)
+	assert.False(t, c.Debug)
+	assert.Equal(t, []string{"cmd", "--verbose", "--debug", "--log", "info", "-t"}, c.Exec.Args)
+
+	// With global:"all" they are also recognised within passthrough arguments, up to "--".
+	c, err = parse("run", "cmd", "--log=debug", "-x", "-vv", "-t", "a", "--", "--log", "x")
+	assert.NoError(t, err)
+	assert.Equal(t, "debug", c.Log)
+	assert.True(t, c.Trace)
+	assert.Equal(t, 0, c.Verbose)
+	assert.Equal(t, []string{"cmd", "-x", "-vv", "a", "--", "--log", "x"}, c.Run.Args)
+
+	c, err = parse("run", "--log", "--", "-t")
+	assert.NoError(t, err)
+	assert.Equal(t, "", c.Log)
+	assert.False(t, c.Trace)
+	assert.Equal(t, []string{"--log", "--", "-t"}, c.Run.Args)
+
+	// Values of other flags are not global flags.
+	c, err = parse("--pattern", "--log", "exec")
+	assert.NoError(t, err)
+	assert.Equal(t, "--log", c.Pattern)
+	assert.Equal(t, "", c.Log)
+
+	var bad struct {
+		Cmd struct {
+			Flag bool `global:""`
+		} `cmd:""`
+	}
+	_, err = kong.New(&bad)
+	assert.EqualError(t, err, "<anonymous struct>.Flag: global flag --flag must be declared on the application")
+
+	var badArg struct {
+		Arg string `arg:"" global:""`
+	}
+	_, err = kong.New(&badArg)
+	assert.EqualError(t, err, "<anonymous struct>.Arg: global only makes sense for flags")
+
+	var badMode struct {
+		Flag bool `global:"always"`
+	}
+	_, err = kong.New(&badMode)
+	assert.EqualError(t, err, "<anonymous struct>.Flag: invalid global mode \"always\", must be 'all' if given")
+}
+
+func TestFlagInheritance(t *testing.T) {
+	type cli struct {
+		Verbose bool
+		Config  string `nopropagate:""`
+		Token   string `required:"" nopropagate:""`
+		Sub     struct {
+			Leaf struct{} `cmd:""`
+		} `cmd:"" inherit:"-"`
+		Other struct {
+			Name string
+		} `cmd:"" inherit:"verbose"`
+	}
+	parse := func(args ...string) (*cli, error) {
+		t.Helper()
+		var c cli
+		p := mustNew(t, &c)
+		_, err := p.Parse(args)
+		return &c, err
+	}
+
+	c, err := parse("--config=x", "--verbose", "other", "--verbose")
+	assert.NoError(t, err)
+	assert.Equal(t, "x", c.Config)
+	assert.True(t, c.Verbose)
+
+	_, err = parse("other", "--config=x")
+	assert.EqualError(t, err, `unknown flag --config; --config is a flag of the application, not "other"`)
+
+	_, err = parse("sub", "leaf", "--verbose")
+	assert.EqualError(t, err, `unknown flag --verbose; --verbose is a flag of the application, not "sub leaf"`)
+
+	var c2 cli
+	w := &strings.Builder{}
+	p := mustNew(t, &c2, kong.Writers(w, w), kong.Exit(func(int) {}))
+	_, _ = p.Parse([]string{"sub", "leaf", "--help"})
+	assert.Contains(t, w.String(), "-h, --help")
+	assert.NotContains(t, w.String(), "--verbose")
+	assert.NotContains(t, w.String(), "[flags]")
+
+	var bad struct {
+		Cmd struct{} `cmd:"" inherit:"missing"`
+	}
+	_, err = kong.New(&bad)
+	assert.EqualError(t, err, "cmd: can't inherit unknown flag --missing")
+
+	var badFlag struct {
+		Flag bool `inherit:"-"`
+	}
+	_, err = kong.New(&badFlag)
+	assert.EqualError(t, err, "<anonymous struct>.Flag: inherit only makes sense for commands")
+}
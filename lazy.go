@@ -0,0 +1,140 @@
+package kong
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// LazyCommands defers building the flags, arguments and sub-commands of commands until they are
+// needed, reducing the startup cost of large CLIs.
+//
+// Commands are registered with only their name, help, aliases and group. The rest of a command is
+// built when it is selected while parsing, when full help is displayed, or when Node.Build is
+// called, eg. before walking the model for completion. Errors in the grammar of a command are
+// consequently reported when it is built rather than by New.
+//
+// Default and passthrough commands, and branching arguments, are always built immediately.
+// PostBuild options only see the commands that have been built.
+func LazyCommands() Option {
+	return OptionFunc(func(k *Kong) error {
+		k.lazyCommands = true
+		return nil
+	})
+}
+
+// Build the flags, arguments and sub-commands of any lazily built commands under n, see
+// LazyCommands.
+func (n *Node) Build() error {
+	if err := n.buildLazy(); err != nil {
+		return err
+	}
+	for _, child := range n.Children {
+		if err := child.Build(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildHelp sets the help of any commands under n whose help is computed lazily, eg. described
+// external commands.
+func (n *Node) buildHelp() {
+	if n.lazyHelp != nil {
+		n.lazyHelp()
+		n.lazyHelp = nil
+	}
+	for _, child := range n.Children {
+		child.buildHelp()
+	}
+}
+
+// buildLazy builds n itself if it is lazy, but not its children.
+func (n *Node) buildLazy() error {
+	if n.lazy == nil {
+		return nil
+	}
+	if err := n.lazy(); err != nil {
+		return err
+	}
+	n.lazy = nil
+	return nil
+}
+
+// buildLazyChild registers a command without building it.
+func buildLazyChild(k *Kong, node *Node, v reflect.Value, ft reflect.StructField, fv reflect.Value, tag *Tag, name string) error {
+	child := &Node{
+		Type:   CommandNode,
+		Target: fv,
+		Tag:    newEmptyTag(),
+	}
+	if provider, ok := fv.Addr().Interface().(HelpProvider); ok {
+		child.Detail = provider.Help()
+	}
+	child.lazy = func() error { return k.buildLazyNode(child) }
+	return attachChild(k, node, child, v, ft, tag, name)
+}
+
+// buildLazyNode builds the contents of a lazily registered command in place.
+func (k *Kong) buildLazyNode(node *Node) error {
+	// Flags of ancestors are seen, as they would be when building eagerly.
+	seenFlags := map[string]bool{}
+	for parent := node.Parent; parent != nil; parent = parent.Parent {
+		for _, flag := range parent.Flags {
+			for _, key := range k.flagKeys(flag) {
+				seenFlags[key] = true
+			}
+		}
+	}
+	built, err := buildNode(k, node.Target, node.Type, newEmptyTag(), seenFlags)
+	if err != nil {
+		return fmt.Errorf("%s: %w", commandPath(node), err)
+	}
+	if len(built.Positional) > 0 && len(built.Children) > 0 {
+		return fmt.Errorf("%s: can't mix positional arguments and branching arguments", commandPath(node))
+	}
+	node.Flags = built.Flags
+	node.Positional = built.Positional
+	node.Children = built.Children
+	node.DefaultCmd = built.DefaultCmd
+	for _, child := range node.Children {
+		child.Parent = node
+	}
+	for _, fn := range k.onLazyBuild {
+		if err := fn(node); err != nil {
+			return err
+		}
+	}
+	// The help of the node was interpolated when it was registered, so escape it and restore it after.
+	help := node.Help
+	node.Help = strings.ReplaceAll(help, "$", "$$")
+	err = k.interpolate(node)
+	node.Help = help
+	if err != nil {
+		return err
+	}
+	if err := checkFoldedEnums(node); err != nil {
+		return err
+	}
+	return checkInherit(node)
+}
+
+// flagKeys returns the keys used to detect duplicates of flag.
+func (k *Kong) flagKeys(flag *Flag) []string {
+	keys := []string{k.flagKey("--" + flag.Name)}
+	if flag.Short != 0 {
+		keys = append(keys, "-"+string(flag.Short))
+	}
+	if negFlag := negatableFlagName(flag.Name, flag.Tag.Negatable); negFlag != "" {
+		keys = append(keys, k.flagKey(negFlag))
+	}
+	for _, aflag := range flag.Aliases {
+		if utf8.RuneCountInString(aflag) == 1 {
+			keys = append(keys, "-"+aflag)
+		} else {
+			keys = append(keys, k.flagKey("--"+aflag))
+		}
+	}
+	return keys
+}
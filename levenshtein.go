@@ -1,34 +1,31 @@
 package kong
 
-import "unicode/utf8"
-
-// Copied from https://github.com/daviddengcn/go-algs/blob/fe23fabd9d0670e4675326040ba7c285c7117b4c/ed/ed.go#L31
-// License: https://github.com/daviddengcn/go-algs/blob/fe23fabd9d0670e4675326040ba7c285c7117b4c/LICENSE
-func levenshtein(a, b string) int {
-	f := make([]int, utf8.RuneCountInString(b)+1)
-
-	for j := range f {
-		f[j] = j
+// damerauLevenshtein returns the optimal string alignment distance between a and b: the number of
+// insertions, deletions, substitutions and transpositions of adjacent runes needed to turn a into b.
+func damerauLevenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// Three rows of the distance matrix: two rows back, the previous row and the current row.
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
 	}
-
-	for _, ca := range a {
-		j := 1
-		fj1 := f[0] // fj1 is the value of f[j - 1] in last iteration
-		f[0]++
-		for _, cb := range b {
-			mn := min(f[j]+1, f[j-1]+1) // delete & insert
-			if cb != ca {
-				mn = min(mn, fj1+1) // change
-			} else {
-				mn = min(mn, fj1) // matched
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(min(prev[j]+1, curr[j-1]+1), prev[j-1]+cost) // delete, insert & change
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1) // transpose
 			}
-
-			fj1, f[j] = f[j], mn // save f[j] to fj1(j is about to increase), update f[j] to mn
-			j++
 		}
+		prev2, prev, curr = prev, curr, prev2
 	}
-
-	return f[len(f)-1]
+	return prev[len(rb)]
 }
 
 func min(a, b int) int { //nolint:predeclared
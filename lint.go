@@ -0,0 +1,270 @@
+package kong
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity of a Diagnostic.
+type Severity int
+
+// Severities of diagnostics, in increasing order.
+const (
+	// SeverityOff disables a lint rule.
+	SeverityOff Severity = iota
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityOff:
+		return "off"
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// Lint rules, and their default severities.
+var defaultLintSeverities = map[string]Severity{
+	// The grammar fails to build.
+	"build": SeverityError,
+	// A command, flag or argument has no help.
+	"missing-help": SeverityWarning,
+	// The default of an enum is not one of its values.
+	"enum-default": SeverityError,
+	// A flag has the same name, alias or short flag as a flag of a parent command.
+	"shadowed-flag": SeverityWarning,
+	// An xor or and group has a single member.
+	"single-member-group": SeverityWarning,
+	// An environment variable is used by more than one flag.
+	"env-clash": SeverityWarning,
+}
+
+// Diagnostic is a problem with a grammar found by Lint.
+type Diagnostic struct {
+	Rule     string
+	Severity Severity
+	// Path to the command, flag or argument, eg. "serve --port".
+	Path    string
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	if d.Path == "" {
+		return fmt.Sprintf("%s: %s [%s]", d.Severity, d.Message, d.Rule)
+	}
+	return fmt.Sprintf("%s: %s: %s [%s]", d.Severity, d.Path, d.Message, d.Rule)
+}
+
+// LintSeverity sets the severity of a Lint rule. SeverityOff disables it.
+//
+// The rules are "build", "missing-help", "enum-default", "shadowed-flag", "single-member-group"
+// and "env-clash".
+func LintSeverity(rule string, severity Severity) Option {
+	return OptionFunc(func(k *Kong) error {
+		if _, ok := defaultLintSeverities[rule]; !ok {
+			return fmt.Errorf("unknown lint rule %q", rule)
+		}
+		if k.lintSeverities == nil {
+			k.lintSeverities = map[string]Severity{}
+		}
+		k.lintSeverities[rule] = severity
+		return nil
+	})
+}
+
+// Lint builds grammar with options, including any lazily built commands, and returns diagnostics
+// for problems with it. These include any error building it, and violations of style rules that
+// would otherwise go unnoticed, eg. missing help.
+//
+// The severity of each rule may be configured with the LintSeverity option.
+func Lint(grammar any, options ...Option) []Diagnostic {
+	k, err := New(grammar, options...)
+	l := &linter{k: k}
+	if k != nil {
+		l.severities = k.lintSeverities
+	}
+	if err == nil {
+		err = k.Model.Node.Build()
+	}
+	if err != nil {
+		l.report("build", "", "%s", err)
+		return l.diagnostics
+	}
+	l.lintNode(k.Model.Node)
+	l.lintGroups()
+	return l.diagnostics
+}
+
+// LintT is the subset of testing.TB used by AssertLint.
+type LintT interface {
+	Helper()
+	Errorf(format string, args ...any)
+	Logf(format string, args ...any)
+}
+
+// AssertLint fails the test if Lint returns any diagnostics with SeverityError, and logs any
+// others, eg.
+//
+//	func TestCLI(t *testing.T) {
+//		kong.AssertLint(t, &CLI{}, kong.LintSeverity("missing-help", kong.SeverityError))
+//	}
+func AssertLint(t LintT, grammar any, options ...Option) {
+	t.Helper()
+	for _, diagnostic := range Lint(grammar, options...) {
+		if diagnostic.Severity >= SeverityError {
+			t.Errorf("%s", diagnostic)
+		} else {
+			t.Logf("%s", diagnostic)
+		}
+	}
+}
+
+type linter struct {
+	k           *Kong
+	severities  map[string]Severity
+	diagnostics []Diagnostic
+	envs        map[string]string   // Environment variable to the path of the first flag using it.
+	groups      map[string][]string // "xor"/"and" group to the paths of its members.
+	groupOrder  []string
+}
+
+func (l *linter) report(rule, path, format string, args ...any) {
+	severity, ok := l.severities[rule]
+	if !ok {
+		severity = defaultLintSeverities[rule]
+	}
+	if severity == SeverityOff {
+		return
+	}
+	l.diagnostics = append(l.diagnostics, Diagnostic{
+		Rule:     rule,
+		Severity: severity,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (l *linter) lintNode(node *Node) {
+	path := commandPath(node)
+	if node.Type != ApplicationNode && !node.Hidden && node.Help == "" {
+		l.report("missing-help", path, "missing help")
+	}
+	for _, flag := range node.Flags {
+		l.lintFlag(node, flag)
+	}
+	for _, positional := range node.Positional {
+		valuePath := strings.TrimSpace(path + " " + positional.Summary())
+		if positional.Help == "" {
+			l.report("missing-help", valuePath, "missing help")
+		}
+		l.lintEnumDefault(valuePath, positional)
+	}
+	for _, child := range node.Children {
+		l.lintNode(child)
+	}
+}
+
+func (l *linter) lintFlag(node *Node, flag *Flag) {
+	path := strings.TrimSpace(commandPath(node) + " --" + flag.Name)
+	if flag == l.k.helpFlag {
+		return
+	}
+	if !flag.Hidden && flag.Help == "" {
+		l.report("missing-help", path, "missing help")
+	}
+	l.lintEnumDefault(path, flag.Value)
+
+	if node.Type != ApplicationNode {
+		keys := map[string]bool{}
+		for _, key := range l.k.flagKeys(flag) {
+			keys[key] = true
+		}
+	ANCESTORS:
+		for parent := node.Parent; parent != nil; parent = parent.Parent {
+			for _, pflag := range parent.Flags {
+				for _, key := range l.k.flagKeys(pflag) {
+					if keys[key] {
+						l.report("shadowed-flag", path, "%s shadows the flag --%s of %s", key, pflag.Name, nodeDescription(parent))
+						break ANCESTORS
+					}
+				}
+			}
+		}
+	}
+
+	if l.envs == nil {
+		l.envs = map[string]string{}
+	}
+	for _, env := range flag.Envs {
+		if other, ok := l.envs[env]; ok {
+			l.report("env-clash", path, "environment variable %s is also used by %s", env, other)
+			continue
+		}
+		l.envs[env] = path
+	}
+
+	if l.groups == nil {
+		l.groups = map[string][]string{}
+	}
+	for _, group := range flag.Xor {
+		l.addGroupMember("xor", group, path)
+	}
+	for _, group := range flag.And {
+		l.addGroupMember("and", group, path)
+	}
+}
+
+func (l *linter) addGroupMember(kind, group, path string) {
+	key := kind + " group " + fmt.Sprintf("%q", group)
+	if _, ok := l.groups[key]; !ok {
+		l.groupOrder = append(l.groupOrder, key)
+	}
+	l.groups[key] = append(l.groups[key], path)
+}
+
+func (l *linter) lintGroups() {
+	for _, key := range l.groupOrder {
+		if members := l.groups[key]; len(members) == 1 {
+			l.report("single-member-group", members[0], "%s has a single member", key)
+		}
+	}
+}
+
+func (l *linter) lintEnumDefault(path string, value *Value) {
+	if value.Enum == "" || !value.HasDefault || value.Default == "" {
+		return
+	}
+	defaults := []string{value.Default}
+	if value.IsSlice() && value.Tag.Sep != -1 {
+		defaults = strings.Split(value.Default, string(value.Tag.Sep))
+	}
+	enums := value.EnumSlice()
+	for _, dflt := range defaults {
+		found := false
+		for _, enum := range enums {
+			if enum == dflt || (value.Tag.EnumFold && strings.EqualFold(enum, dflt)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			l.report("enum-default", path, "default %q is not one of %s", dflt, strings.Join(enums, ","))
+			return
+		}
+	}
+}
+
+func nodeDescription(node *Node) string {
+	if node.Type == ApplicationNode {
+		return "the application"
+	}
+	return fmt.Sprintf("%q", commandPath(node))
+}
@@ -0,0 +1,99 @@
+package kong_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/alecthomas/kong"
+)
+
+type lintServeCmd struct {
+	Format string `enum:"json,text" default:"yaml" help:"Output format."`
+	Key    string `env:"TOKEN" xor:"auth" help:"Key."`
+	Dir    string `arg:""`
+}
+
+func TestLint(t *testing.T) {
+	var cli struct {
+		Debug    bool         `short:"d" help:"Enable debugging."`
+		Token    string       `env:"TOKEN" help:"Token."`
+		Secret   string       `hidden:""`
+		Serve    lintServeCmd `cmd:""`
+		Internal struct{}     `cmd:"" hidden:""`
+	}
+	diagnostics := kong.Lint(&cli, kong.DynamicCommand("debug", "", "", &struct {
+		Verbose bool `short:"d" help:"Verbose."`
+	}{}))
+	actual := []string{}
+	for _, diagnostic := range diagnostics {
+		actual = append(actual, diagnostic.String())
+	}
+	assert.Equal(t, []string{
+		"warning: serve: missing help [missing-help]",
+		`error: serve --format: default "yaml" is not one of json,text [enum-default]`,
+		"warning: serve --key: environment variable TOKEN is also used by --token [env-clash]",
+		"warning: serve <dir>: missing help [missing-help]",
+		"warning: debug: missing help [missing-help]",
+		"warning: debug --verbose: -d shadows the flag --debug of the application [shadowed-flag]",
+		`warning: serve --key: xor group "auth" has a single member [single-member-group]`,
+	}, actual)
+
+	diagnostics = kong.Lint(&cli,
+		kong.LintSeverity("missing-help", kong.SeverityOff),
+		kong.LintSeverity("shadowed-flag", kong.SeverityOff),
+		kong.LintSeverity("env-clash", kong.SeverityInfo),
+		kong.LintSeverity("enum-default", kong.SeverityWarning))
+	assert.Equal(t, []kong.Diagnostic{
+		{Rule: "enum-default", Severity: kong.SeverityWarning, Path: "serve --format", Message: `default "yaml" is not one of json,text`},
+		{Rule: "env-clash", Severity: kong.SeverityInfo, Path: "serve --key", Message: "environment variable TOKEN is also used by --token"},
+		{Rule: "single-member-group", Severity: kong.SeverityWarning, Path: "serve --key", Message: `xor group "auth" has a single member`},
+	}, diagnostics)
+}
+
+func TestLintBuildError(t *testing.T) {
+	var cli struct {
+		Flag string `short:"f" help:"Flag."`
+		Cmd  struct {
+			Other string `short:"f" help:"Other."`
+		} `cmd:"" help:"Command."`
+	}
+	assert.Equal(t, []kong.Diagnostic{
+		{Rule: "build", Severity: kong.SeverityError, Message: "<anonymous struct>.Other: duplicate short flag -f"},
+	}, kong.Lint(&cli))
+
+	assert.Equal(t, []kong.Diagnostic{
+		{Rule: "build", Severity: kong.SeverityError, Message: `unknown lint rule "missing"`},
+	}, kong.Lint(&cli, kong.LintSeverity("missing", kong.SeverityOff)))
+}
+
+type lintT struct {
+	errors []string
+	logs   []string
+}
+
+func (*lintT) Helper() {}
+func (l *lintT) Errorf(format string, args ...any) {
+	l.errors = append(l.errors, fmt.Sprintf(format, args...))
+}
+func (l *lintT) Logf(format string, args ...any) {
+	l.logs = append(l.logs, fmt.Sprintf(format, args...))
+}
+
+func TestAssertLint(t *testing.T) {
+	var cli struct {
+		Flag  string `help:"Flag."`
+		Other string
+	}
+	lt := &lintT{}
+	kong.AssertLint(lt, &cli)
+	assert.Equal(t, []string(nil), lt.errors)
+	assert.Equal(t, []string{"warning: --other: missing help [missing-help]"}, lt.logs)
+
+	lt = &lintT{}
+	kong.AssertLint(lt, &cli, kong.LintSeverity("missing-help", kong.SeverityError))
+	assert.Equal(t, []string{"error: --other: missing help [missing-help]"}, lt.errors)
+
+	kong.AssertLint(t, &cli, kong.LintSeverity("missing-help", kong.SeverityOff))
+}
@@ -6,10 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"math/bits"
+	"net"
+	"net/netip"
 	"net/url"
 	"os"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -31,13 +35,45 @@ type DecodeContext struct {
 	Value *Value
 	// Scan contains the input to scan into Target.
 	Scan *Scanner
+
+	kong     *Kong        // Parser decoding the value, if any.
+	seenKeys map[any]bool // Map keys already decoded into the target, for "nodupes".
 }
 
 // WithScanner creates a clone of this context with a new Scanner.
 func (r *DecodeContext) WithScanner(scan *Scanner) *DecodeContext {
 	return &DecodeContext{
-		Value: r.Value,
-		Scan:  scan,
+		Value:    r.Value,
+		Scan:     scan,
+		kong:     r.kong,
+		seenKeys: r.seenKeys,
+	}
+}
+
+// suggestOptions returns the SuggestOptions of the parser decoding the value.
+func (r *DecodeContext) suggestOptions() SuggestOptions {
+	if r.kong == nil {
+		return DefaultSuggestOptions
+	}
+	return r.kong.suggestOptions
+}
+
+// PopString pops the next value as a string. Numeric values, eg. from configuration files, are
+// formatted as strings.
+//
+// "context" is used to assist the user if the value can not be popped, eg. "expected <context> value but got <type>"
+func (r *DecodeContext) PopString(context string) (string, error) {
+	t, err := r.Scan.PopValue(context)
+	if err != nil {
+		return "", err
+	}
+	switch v := t.Value.(type) {
+	case string:
+		return v, nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("expected %s but got %q (%T)", context, t.Value, t.Value)
 	}
 }
 
@@ -146,6 +182,19 @@ func (m MapperFunc) Decode(ctx *DecodeContext, target reflect.Value) error { //n
 	return m(ctx, target)
 }
 
+// TypeMapperFunc returns a Mapper that decodes a single value into a T using parse.
+//
+// The Mapper provides a placeholder derived from the name of T, eg. LOG-LEVEL for LogLevel.
+// Slices, pointers and maps of T reuse the Mapper if it is registered for T, eg. with BindMapper.
+func TypeMapperFunc[T any](parse func(string) (T, error)) Mapper {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	name := dashedString(typ.Name())
+	if name == "" {
+		name = typ.String()
+	}
+	return parseMapper(strings.ToLower(name), strings.ToUpper(name), parse)
+}
+
 // A Registry contains a set of mappers and supporting lookup methods.
 type Registry struct {
 	names  map[string]Mapper
@@ -209,6 +258,10 @@ func (r *Registry) ForType(typ reflect.Type) Mapper {
 	if mapper, ok = r.types[typ]; ok {
 		return mapper
 	}
+	// Next, types with a fixed set of values.
+	if _, ok := enumValues(typ); ok {
+		return enumMapper{}
+	}
 	// Next try stdlib unmarshaler interfaces.
 	for _, impl := range []reflect.Type{typ, reflect.PtrTo(typ)} {
 		switch {
@@ -282,14 +335,34 @@ func (r *Registry) RegisterDefaults() *Registry {
 		RegisterKind(reflect.Slice, sliceDecoder(r)).
 		RegisterKind(reflect.Map, mapDecoder(r)).
 		RegisterType(reflect.TypeOf(time.Time{}), timeDecoder()).
-		RegisterType(reflect.TypeOf(time.Duration(0)), durationDecoder()).
+		RegisterType(reflect.TypeOf(time.Duration(0)), durationDecoder(false)).
 		RegisterType(reflect.TypeOf(&url.URL{}), urlMapper()).
 		RegisterType(reflect.TypeOf(&os.File{}), fileMapper(r)).
+		RegisterType(reflect.TypeOf(net.IP{}), parseMapper("IP address", "IP", parseIP)).
+		RegisterType(reflect.TypeOf(net.IPNet{}), parseMapper("CIDR", "CIDR", parseIPNet)).
+		RegisterType(reflect.TypeOf(&net.IPNet{}), parseMapper("CIDR", "CIDR", func(s string) (*net.IPNet, error) {
+			n, err := parseIPNet(s)
+			return &n, err
+		})).
+		RegisterType(reflect.TypeOf(netip.Addr{}), parseMapper("IP address", "IP", netip.ParseAddr)).
+		RegisterType(reflect.TypeOf(netip.Prefix{}), parseMapper("CIDR", "CIDR", netip.ParsePrefix)).
+		RegisterType(reflect.TypeOf(netip.AddrPort{}), parseMapper("IP address and port", "IP:PORT", netip.ParseAddrPort)).
+		RegisterType(reflect.TypeOf(&regexp.Regexp{}), parseMapper("regular expression", "REGEX", regexp.Compile)).
+		RegisterType(reflect.TypeOf(&big.Int{}), parseMapper("integer", "INT", parseBigInt)).
+		RegisterType(reflect.TypeOf(&big.Float{}), parseMapper("float", "FLOAT", parseBigFloat)).
+		RegisterType(reflect.TypeOf(&big.Rat{}), parseMapper("rational number", "RATIONAL", parseBigRat)).
+		RegisterType(reflect.TypeOf(&time.Location{}), parseMapper("time zone", "TZ", time.LoadLocation)).
 		RegisterName("path", pathMapper(r)).
 		RegisterName("existingfile", existingFileMapper(r)).
 		RegisterName("existingdir", existingDirMapper(r)).
 		RegisterName("counter", counterMapper()).
 		RegisterName("filecontent", fileContentMapper(r)).
+		RegisterName("bytesize", byteSizeMapper()).
+		RegisterName("hostport", hostPortMapper()).
+		RegisterName("outputfile", outputFileMapper()).
+		RegisterName("struct", newStructMapper(r)).
+		RegisterName("glob", globMapper(false)).
+		RegisterName("existingglob", globMapper(true)).
 		RegisterKind(reflect.Ptr, ptrMapper{r})
 }
 
@@ -325,7 +398,7 @@ func (boolMapper) Decode(ctx *DecodeContext, target reflect.Value) error {
 }
 func (boolMapper) IsBool() bool { return true }
 
-func durationDecoder() MapperFunc {
+func durationDecoder(extended bool) MapperFunc {
 	return func(ctx *DecodeContext, target reflect.Value) error {
 		t, err := ctx.Scan.PopValue("duration")
 		if err != nil {
@@ -334,7 +407,7 @@ func durationDecoder() MapperFunc {
 		var d time.Duration
 		switch v := t.Value.(type) {
 		case string:
-			d, err = time.ParseDuration(v)
+			d, err = parseDuration(v, extended)
 			if err != nil {
 				return fmt.Errorf("expected duration but got %q: %v", v, err)
 			}
@@ -348,23 +421,159 @@ func durationDecoder() MapperFunc {
 	}
 }
 
-func timeDecoder() MapperFunc {
-	return func(ctx *DecodeContext, target reflect.Value) error {
-		format := time.RFC3339
-		if ctx.Value.Format != "" {
-			format = ctx.Value.Format
+// parseDuration parses a duration with time.ParseDuration, additionally accepting days (d) and
+// weeks (w) if extended, eg. 1w2d or 1.5d12h.
+func parseDuration(s string, extended bool) (time.Duration, error) {
+	if !extended {
+		return time.ParseDuration(s)
+	}
+	rest := strings.TrimLeft(s, "+-")
+	isNumber := func(r rune) bool { return (r >= '0' && r <= '9') || r == '.' }
+	days := 0.0
+	other := ""
+	for rest != "" {
+		i := strings.IndexFunc(rest, func(r rune) bool { return !isNumber(r) })
+		if i <= 0 {
+			// Not a number followed by a unit, let the stdlib report it.
+			return time.ParseDuration(s)
 		}
-		var value string
-		if err := ctx.Scan.PopValueInto("time", &value); err != nil {
-			return err
+		j := strings.IndexFunc(rest[i:], isNumber)
+		if j < 0 {
+			j = len(rest) - i
 		}
-		t, err := time.Parse(format, value)
-		if err != nil {
+		number, unit := rest[:i], rest[i:i+j]
+		rest = rest[i+j:]
+		switch unit {
+		case "d", "w":
+			n, err := strconv.ParseFloat(number, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			if unit == "w" {
+				n *= 7
+			}
+			days += n
+
+		default:
+			other += number + unit
+		}
+	}
+	var d time.Duration
+	if other != "" {
+		var err error
+		if d, err = time.ParseDuration(other); err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+	}
+	d += time.Duration(days * float64(24*time.Hour))
+	if strings.HasPrefix(s, "-") {
+		d = -d
+	}
+	return d, nil
+}
+
+// defaultTimeLayouts are accepted by time.Time values without a format tag.
+var defaultTimeLayouts = []string{time.RFC3339, time.DateOnly}
+
+// timeMapper decodes time.Time values.
+//
+// Values may be in any of the layouts given by "format" tags, a relative time or a Unix
+// timestamp in seconds or milliseconds prefixed with "@". Times without a zone are interpreted in the location
+// given by the "tz" tag, defaulting to UTC.
+type timeMapper struct{}
+
+func timeDecoder() Mapper { return timeMapper{} }
+
+func (timeMapper) Decode(ctx *DecodeContext, target reflect.Value) error {
+	t, err := ctx.Scan.PopValue("time")
+	if err != nil {
+		return err
+	}
+	var value string
+	switch v := t.Value.(type) {
+	case string:
+		value = v
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		// Numbers from configuration are Unix timestamps.
+		value = "@" + strconv.FormatFloat(reflect.ValueOf(v).Convert(reflect.TypeOf(float64(0))).Float(), 'f', -1, 64)
+	default:
+		return fmt.Errorf("expected time but got %q (%T)", t.Value, t.Value)
+	}
+	loc := time.UTC
+	if ctx.Value.Tag != nil && ctx.Value.Tag.TZ != "" {
+		if loc, err = time.LoadLocation(ctx.Value.Tag.TZ); err != nil {
 			return err
 		}
-		target.Set(reflect.ValueOf(t))
-		return nil
 	}
+	tm, err := parseTime(value, timeLayouts(ctx.Value.Tag), loc, time.Now())
+	if err != nil {
+		return err
+	}
+	target.Set(reflect.ValueOf(tm))
+	return nil
+}
+
+// typePlaceHolder is the layouts given by "format" tags, or "<time>".
+func (timeMapper) typePlaceHolder(flag *Flag) string {
+	if flag.Tag != nil {
+		if layouts := flag.Tag.GetAll("format"); len(layouts) > 0 {
+			return strings.Join(layouts, "|")
+		}
+	}
+	return "<time>"
+}
+
+// timeLayouts returns the layouts from "format" tags, or the default layouts.
+func timeLayouts(tag *Tag) []string {
+	if tag != nil {
+		if layouts := tag.GetAll("format"); len(layouts) > 0 {
+			return layouts
+		}
+	}
+	return append([]string(nil), defaultTimeLayouts...)
+}
+
+// parseTime parses value with the first matching layout, as a relative time, or as a Unix
+// timestamp prefixed with "@". Timestamps with at least 13 digits are in milliseconds.
+func parseTime(value string, layouts []string, loc *time.Location, now time.Time) (time.Time, error) {
+	var err error
+	for _, layout := range layouts {
+		var t time.Time
+		if t, err = time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	now = now.In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	switch strings.ToLower(value) {
+	case "now":
+		return now, nil
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+	if strings.HasPrefix(value, "+") || strings.HasPrefix(value, "-") {
+		if d, derr := parseDuration(value, true); derr == nil {
+			return now.Add(d), nil
+		}
+	}
+	if epoch, ok := strings.CutPrefix(value, "@"); ok {
+		n, nerr := strconv.ParseInt(epoch, 10, 64)
+		if nerr != nil {
+			return time.Time{}, fmt.Errorf("expected Unix timestamp but got %q", value)
+		}
+		if n >= 1e12 || n <= -1e12 {
+			return time.UnixMilli(n).In(loc), nil
+		}
+		return time.Unix(n, 0).In(loc), nil
+	}
+	if len(layouts) == 1 {
+		return time.Time{}, err
+	}
+	return time.Time{}, fmt.Errorf("expected time in one of the formats %q, a relative time or a Unix timestamp (@SECONDS) but got %q", layouts, value)
 }
 
 func intDecoder(bits int) MapperFunc { //nolint: dupl
@@ -520,8 +729,23 @@ func mapDecoder(r *Registry) MapperFunc {
 				return fmt.Errorf("invalid map key %q", key)
 			}
 
+			if ctx.Value.Tag.NoDupes {
+				if ctx.seenKeys[keyValue.Interface()] {
+					return fmt.Errorf("duplicate map key %q", key)
+				}
+				if ctx.seenKeys == nil {
+					ctx.seenKeys = map[any]bool{}
+				}
+				ctx.seenKeys[keyValue.Interface()] = true
+			}
+
 			valueScanner := ScanAsType(FlagValueToken, value)
 			valueDecoder := r.ForNamedType(valueTypeName, el.Elem())
+			if name, ok := ctx.Value.Tag.ValueTypes[key]; ok {
+				if valueDecoder = r.names[name]; valueDecoder == nil {
+					return fmt.Errorf("unknown value type %q for map key %q", name, key)
+				}
+			}
 			valueValue := reflect.New(el.Elem()).Elem()
 			if err := valueDecoder.Decode(ctx.WithScanner(valueScanner), valueValue); err != nil {
 				return fmt.Errorf("invalid map value %q", value)
@@ -567,6 +791,9 @@ func sliceDecoder(r *Registry) MapperFunc {
 		if childDecoder == nil {
 			return fmt.Errorf("no mapper for element type of %s", target.Type())
 		}
+		if ctx.Value.Enum != "" {
+			childScanner = expandEnumTokens(ctx.Value, childScanner, target)
+		}
 		for !childScanner.Peek().IsEOL() {
 			childValue := reflect.New(el).Elem()
 			err := childDecoder.Decode(ctx.WithScanner(childScanner), childValue)
@@ -579,6 +806,28 @@ func sliceDecoder(r *Registry) MapperFunc {
 	}
 }
 
+// expandEnumTokens replaces an "all" element of an enum slice with every enum value, and clears
+// the slice on a "none" element, unless they are themselves enum values.
+func expandEnumTokens(value *Value, scan *Scanner, target reflect.Value) *Scanner {
+	enums := value.EnumMap()
+	tokens := []Token{}
+	for !scan.Peek().IsEOL() {
+		t := scan.Pop()
+		switch s, _ := t.Value.(string); {
+		case s == "all" && !enums["all"]:
+			for _, enum := range value.EnumSlice() {
+				tokens = append(tokens, Token{Type: t.Type, Value: enum})
+			}
+		case s == "none" && !enums["none"]:
+			tokens = tokens[:0]
+			target.Set(reflect.MakeSlice(target.Type(), 0, 0))
+		default:
+			tokens = append(tokens, t)
+		}
+	}
+	return ScanFromTokens(tokens...)
+}
+
 func pathMapper(r *Registry) MapperFunc {
 	return func(ctx *DecodeContext, target reflect.Value) error {
 		if target.Kind() == reflect.Slice {
@@ -963,3 +1212,175 @@ func jsonTranscode(in, out any) error {
 	}
 	return nil
 }
+
+// typePlaceHolderProvider is implemented by builtin mappers with a placeholder for their type,
+// which FormatPlaceHolder uses unless the flag has an explicit placeholder or a default.
+type typePlaceHolderProvider interface {
+	typePlaceHolder(flag *Flag) string
+}
+
+// placeHolderMapper is a Mapper with a fixed placeholder for its type.
+type placeHolderMapper struct {
+	MapperFunc
+	placeholder string
+}
+
+func (p placeHolderMapper) typePlaceHolder(*Flag) string { return p.placeholder }
+
+// parseMapper returns a Mapper that decodes a single scalar value with parse.
+//
+// "context" describes the expected value in errors.
+func parseMapper[T any](context, placeholder string, parse func(string) (T, error)) Mapper {
+	return placeHolderMapper{
+		MapperFunc: func(ctx *DecodeContext, target reflect.Value) error {
+			value, err := ctx.PopString(context)
+			if err != nil {
+				return err
+			}
+			v, err := parse(value)
+			if err != nil {
+				return fmt.Errorf("expected %s but got %q: %w", context, value, err)
+			}
+			target.Set(reflect.ValueOf(v))
+			return nil
+		},
+		placeholder: placeholder,
+	}
+}
+
+func parseIP(s string) (net.IP, error) {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, errors.New("invalid IP address")
+	}
+	return ip, nil
+}
+
+func parseIPNet(s string) (net.IPNet, error) {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		return net.IPNet{}, err
+	}
+	return *n, nil
+}
+
+func parseBigInt(s string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return nil, errors.New("invalid integer")
+	}
+	return n, nil
+}
+
+func parseBigFloat(s string) (*big.Float, error) {
+	n, ok := new(big.Float).SetString(s)
+	if !ok {
+		return nil, errors.New("invalid float")
+	}
+	return n, nil
+}
+
+func parseBigRat(s string) (*big.Rat, error) {
+	n, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, errors.New("invalid rational number")
+	}
+	return n, nil
+}
+
+// byteSizeUnits maps case-insensitive byte size suffixes to their multipliers. SI suffixes, with
+// or without a trailing "b", are decimal, eg. k and kb are 1000, while IEC suffixes, with or
+// without a trailing "b", are binary, eg. ki and kib are 1024.
+var byteSizeUnits = func() map[string]int64 {
+	units := map[string]int64{"": 1, "b": 1}
+	decimal, binary := int64(1), int64(1)
+	for _, prefix := range []string{"k", "m", "g", "t", "p", "e"} {
+		decimal *= 1000
+		binary *= 1 << 10
+		units[prefix] = decimal
+		units[prefix+"b"] = decimal
+		units[prefix+"i"] = binary
+		units[prefix+"ib"] = binary
+	}
+	return units
+}()
+
+// parseByteSize parses a size such as 10MiB or 1.5GB into a number of bytes.
+func parseByteSize(s string) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+	number, unit := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+	multiplier, ok := byteSizeUnits[unit]
+	if !ok {
+		return nil, fmt.Errorf("unknown unit %q", s[i:])
+	}
+	n, ok := new(big.Rat).SetString(number)
+	if number == "" || !ok {
+		return nil, errors.New("invalid size")
+	}
+	n.Mul(n, new(big.Rat).SetInt64(multiplier))
+	if !n.IsInt() {
+		return nil, errors.New("size must be a whole number of bytes")
+	}
+	return n.Num(), nil
+}
+
+func byteSizeMapper() Mapper {
+	return placeHolderMapper{
+		MapperFunc: func(ctx *DecodeContext, target reflect.Value) error {
+			value, err := ctx.PopString("byte size")
+			if err != nil {
+				return err
+			}
+			n, err := parseByteSize(value)
+			if err != nil {
+				return fmt.Errorf("expected byte size but got %q: %w", value, err)
+			}
+			switch target.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				if !n.IsInt64() || target.OverflowInt(n.Int64()) {
+					return fmt.Errorf("byte size %q overflows %s", value, target.Type())
+				}
+				target.SetInt(n.Int64())
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				if !n.IsUint64() || target.OverflowUint(n.Uint64()) {
+					return fmt.Errorf("byte size %q overflows %s", value, target.Type())
+				}
+				target.SetUint(n.Uint64())
+			default:
+				return fmt.Errorf("type:\"bytesize\" must be used with an integer field")
+			}
+			return nil
+		},
+		placeholder: "SIZE",
+	}
+}
+
+func hostPortMapper() Mapper {
+	return placeHolderMapper{
+		MapperFunc: func(ctx *DecodeContext, target reflect.Value) error {
+			if target.Kind() != reflect.String {
+				return fmt.Errorf("type:\"hostport\" must be used with a string field")
+			}
+			value, err := ctx.PopString("host:port")
+			if err != nil {
+				return err
+			}
+			_, port, err := net.SplitHostPort(value)
+			if err != nil {
+				return fmt.Errorf("expected host:port but got %q: %w", value, err)
+			}
+			if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+				return fmt.Errorf("expected host:port but got %q: invalid port %q", value, port)
+			}
+			target.SetString(value)
+			return nil
+		},
+		placeholder: "HOST:PORT",
+	}
+}
@@ -3,12 +3,18 @@ package kong_test
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math"
+	"math/big"
+	"net"
+	"net/netip"
 	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -138,6 +144,115 @@ func TestDurationMapperJSONResolver(t *testing.T) {
 	assert.Equal(t, time.Second*5, cli.Flag)
 }
 
+func TestTimeMapperLayouts(t *testing.T) {
+	var cli struct {
+		Default time.Time
+		Flag    time.Time `format:"2006-01-02" format:"2006-01-02 15:04"`
+		Local   time.Time `format:"2006-01-02 15:04" tz:"America/New_York"`
+	}
+	p := mustNew(t, &cli)
+	_, err := p.Parse([]string{"--default=2024-03-01", "--flag=2024-03-01 12:30", "--local=2024-03-01 12:30"})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), cli.Default)
+	assert.Equal(t, time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC), cli.Flag)
+	assert.Equal(t, time.Date(2024, 3, 1, 17, 30, 0, 0, time.UTC), cli.Local.UTC())
+
+	_, err = p.Parse([]string{"--flag=March"})
+	assert.EqualError(t, err, `--flag: expected time in one of the formats ["2006-01-02" "2006-01-02 15:04"], a relative time or a Unix timestamp (@SECONDS) but got "March"`)
+
+	var invalid struct {
+		Flag time.Time `tz:"Nowhere/Special"`
+	}
+	_, err = kong.New(&invalid)
+	assert.Error(t, err)
+}
+
+func TestTimeMapperRelativeAndUnix(t *testing.T) {
+	var cli struct {
+		Flag time.Time
+	}
+	p := mustNew(t, &cli)
+	before := time.Now()
+	_, err := p.Parse([]string{"--flag=now"})
+	assert.NoError(t, err)
+	assert.True(t, !cli.Flag.Before(before) && !cli.Flag.After(time.Now()))
+
+	_, err = p.Parse([]string{"--flag=-2h"})
+	assert.NoError(t, err)
+	assert.True(t, time.Since(cli.Flag) >= 2*time.Hour && time.Since(cli.Flag) < 2*time.Hour+time.Minute)
+
+	_, err = p.Parse([]string{"--flag=+1d"})
+	assert.NoError(t, err)
+	assert.True(t, time.Until(cli.Flag) > 23*time.Hour)
+
+	_, err = p.Parse([]string{"--flag=yesterday"})
+	assert.NoError(t, err)
+	now := time.Now().UTC()
+	assert.Equal(t, time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, time.UTC), cli.Flag)
+
+	_, err = p.Parse([]string{"--flag=@1700000000"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1700000000), cli.Flag.Unix())
+
+	_, err = p.Parse([]string{"--flag=@1700000000123"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1700000000123), cli.Flag.UnixMilli())
+
+	// Digits without "@" are not a timestamp.
+	_, err = p.Parse([]string{"--flag=1700000000"})
+	assert.Error(t, err)
+
+	_, err = p.Parse([]string{"--flag=@soon"})
+	assert.EqualError(t, err, `--flag: expected Unix timestamp but got "@soon"`)
+
+	resolver, err := kong.JSON(strings.NewReader(`{"flag": 1700000000}`))
+	assert.NoError(t, err)
+	_, err = mustNew(t, &cli, kong.Resolvers(resolver)).Parse(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1700000000), cli.Flag.Unix())
+}
+
+func TestTimeMapperPlaceHolder(t *testing.T) {
+	var cli struct {
+		Since time.Time
+		Day   time.Time `format:"2006-01-02" format:"2006-01-02 15:04"`
+		Until time.Time `placeholder:"WHEN"`
+	}
+	b := bytes.NewBuffer(nil)
+	p := mustNew(t, &cli, kong.Writers(b, b), kong.Exit(func(int) { panic("exit") }))
+	assert.Panics(t, func() {
+		_, err := p.Parse([]string{"--help"})
+		assert.NoError(t, err)
+	})
+	assert.Contains(t, b.String(), "--since=<time>")
+	assert.Contains(t, b.String(), "--day=2006-01-02|2006-01-02 15:04")
+	assert.Contains(t, b.String(), "--until=WHEN")
+}
+
+func TestExtendedDurations(t *testing.T) {
+	var cli struct {
+		Flag time.Duration
+	}
+	_, err := mustNew(t, &cli).Parse([]string{"--flag=1d"})
+	assert.Error(t, err)
+
+	p := mustNew(t, &cli, kong.ExtendedDurations())
+	for input, expected := range map[string]time.Duration{
+		"1d":      24 * time.Hour,
+		"1w2d":    9 * 24 * time.Hour,
+		"1.5d12h": 48 * time.Hour,
+		"-1d30m":  -(24*time.Hour + 30*time.Minute),
+		"90s":     90 * time.Second,
+		"0":       0,
+	} {
+		_, err := p.Parse([]string{"--flag=" + input})
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, cli.Flag, input)
+	}
+	_, err = p.Parse([]string{"--flag=1x"})
+	assert.EqualError(t, err, `--flag: expected duration but got "1x": invalid duration "1x"`)
+}
+
 func TestSplitEscaped(t *testing.T) {
 	assert.Equal(t, []string{"a", "b"}, kong.SplitEscaped("a,b", ','))
 	assert.Equal(t, []string{"a,b", "c"}, kong.SplitEscaped(`a\,b,c`, ','))
@@ -196,6 +311,83 @@ func TestMapWithNoSeparator(t *testing.T) {
 	assert.Equal(t, map[string]string{"a": "b;n=d"}, cli.Value)
 }
 
+func TestMapKeyConstraints(t *testing.T) {
+	var cli struct {
+		Feature map[string]bool   `keyenum:"alpha,beta,gamma" requiredkeys:"alpha" help:"Features."`
+		Label   map[string]string `keyformat:"[a-z][a-z0-9.-]*" nodupes:""`
+		Limit   map[string]string `valuetypes:"size=bytesize,addr=hostport"`
+	}
+	k := mustNew(t, &cli)
+	_, err := k.Parse([]string{"--feature=alpha=true;beta=false", "--label=app=web", "--label=tier=db"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"alpha": true, "beta": false}, cli.Feature)
+	assert.Equal(t, map[string]string{"app": "web", "tier": "db"}, cli.Label)
+
+	k = mustNew(t, &cli)
+	_, err = k.Parse([]string{"--feature=alpha=true;betta=true"})
+	assert.EqualError(t, err, `--feature: map key must be one of "alpha","beta","gamma" but got "betta", did you mean "beta"?`)
+
+	k = mustNew(t, &cli)
+	_, err = k.Parse([]string{"--feature=beta=true"})
+	assert.EqualError(t, err, `--feature: missing required key "alpha"`)
+
+	k = mustNew(t, &cli)
+	_, err = k.Parse([]string{"--label=App=web"})
+	assert.EqualError(t, err, `--label: map key "App" does not match "[a-z][a-z0-9.-]*"`)
+
+	k = mustNew(t, &cli)
+	_, err = k.Parse([]string{"--label=app=web", "--label=app=api"})
+	assert.EqualError(t, err, `--label: duplicate map key "app"`)
+
+	k = mustNew(t, &cli, kong.SuggestOptions{})
+	_, err = k.Parse([]string{"--feature=alpha=true;betta=true"})
+	assert.EqualError(t, err, `--feature: map key must be one of "alpha","beta","gamma" but got "betta"`)
+
+	k = mustNew(t, &cli)
+	_, err = k.Parse([]string{"--limit=addr=:8080;other=x"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"addr": ":8080", "other": "x"}, cli.Limit)
+	_, err = k.Parse([]string{"--limit=addr=8080"})
+	assert.Error(t, err)
+
+	w := &strings.Builder{}
+	k = mustNew(t, &cli, kong.Writers(w, w), kong.Exit(func(int) {}))
+	_, _ = k.Parse([]string{"--help"})
+	assert.Contains(t, w.String(), "--feature={alpha,beta,gamma}=VALUE;...")
+	assert.Contains(t, w.String(), "Features. Required keys: alpha.")
+}
+
+func TestMapNoDupesDefault(t *testing.T) {
+	var cli struct {
+		Label map[string]string `nodupes:"" default:"app=web;tier=db"`
+	}
+	k := mustNew(t, &cli)
+	_, err := k.Parse([]string{"--label=app=api"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"app": "api"}, cli.Label)
+
+	_, err = k.Parse([]string{"--label=app=api", "--label=app=web"})
+	assert.EqualError(t, err, `--label: duplicate map key "app"`)
+}
+
+func TestMapKeyConstraintsInvalid(t *testing.T) {
+	var notMap struct {
+		Flag string `nodupes:""`
+	}
+	_, err := kong.New(&notMap)
+	assert.EqualError(t, err, "<anonymous struct>.Flag: keyenum, keyformat, requiredkeys, nodupes and valuetypes can only be applied to maps")
+	var required struct {
+		Flag map[string]string `keyenum:"a,b" requiredkeys:"c"`
+	}
+	_, err = kong.New(&required)
+	assert.EqualError(t, err, `<anonymous struct>.Flag: required key "c" is not in keyenum`)
+	var format struct {
+		Flag map[string]string `keyformat:"["`
+	}
+	_, err = kong.New(&format)
+	assert.Error(t, err)
+}
+
 func TestURLMapper(t *testing.T) {
 	var cli struct {
 		URL *url.URL `arg:""`
@@ -897,3 +1089,391 @@ func TestFileMapperWithDefaultNonExistentFile(t *testing.T) {
 	assert.Contains(t, err.Error(), "missing-default.txt")
 	assert.IsError(t, err, os.ErrNotExist)
 }
+
+func TestNetMappers(t *testing.T) {
+	var cli struct {
+		IP       net.IP
+		Net      net.IPNet
+		NetPtr   *net.IPNet
+		Addr     netip.Addr
+		Prefix   netip.Prefix
+		AddrPort netip.AddrPort
+	}
+	p := mustNew(t, &cli)
+	_, err := p.Parse([]string{
+		"--ip=10.0.0.1", "--net=10.0.0.0/8", "--net-ptr=::1/128",
+		"--addr=::1", "--prefix=192.168.0.0/16", "--addr-port=127.0.0.1:8080",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", cli.IP.String())
+	assert.Equal(t, "10.0.0.0/8", cli.Net.String())
+	assert.Equal(t, "::1/128", cli.NetPtr.String())
+	assert.Equal(t, netip.MustParseAddr("::1"), cli.Addr)
+	assert.Equal(t, netip.MustParsePrefix("192.168.0.0/16"), cli.Prefix)
+	assert.Equal(t, netip.MustParseAddrPort("127.0.0.1:8080"), cli.AddrPort)
+
+	_, err = p.Parse([]string{"--ip=10.0.0"})
+	assert.EqualError(t, err, `--ip: expected IP address but got "10.0.0": invalid IP address`)
+}
+
+func TestStdlibMappers(t *testing.T) {
+	var cli struct {
+		Regex    *regexp.Regexp
+		Int      *big.Int
+		Float    *big.Float
+		Rat      *big.Rat
+		Location *time.Location
+	}
+	p := mustNew(t, &cli)
+	_, err := p.Parse([]string{
+		"--regex=^a+$", "--int=0x1fffffffffffffffff", "--float=1.5", "--rat=1/3", "--location=UTC",
+	})
+	assert.NoError(t, err)
+	assert.True(t, cli.Regex.MatchString("aaa"))
+	assert.Equal(t, "590295810358705651711", cli.Int.String())
+	assert.Equal(t, "1.5", cli.Float.String())
+	assert.Equal(t, "1/3", cli.Rat.String())
+	assert.Equal(t, time.UTC, cli.Location)
+
+	_, err = p.Parse([]string{"--regex=("})
+	assert.Error(t, err)
+	_, err = p.Parse([]string{"--int=1.5"})
+	assert.EqualError(t, err, `--int: expected integer but got "1.5": invalid integer`)
+}
+
+func TestByteSizeMapper(t *testing.T) {
+	var cli struct {
+		Size  int64  `type:"bytesize"`
+		Small uint16 `type:"bytesize"`
+	}
+	p := mustNew(t, &cli)
+	for input, expected := range map[string]int64{
+		"512":    512,
+		"10MiB":  10 << 20,
+		"1.5GB":  1500000000,
+		"2k":     2000,
+		"2Ki":    2048,
+		"1.5 kb": 1500,
+		"1KiB":   1024,
+	} {
+		_, err := p.Parse([]string{"--size", input})
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, cli.Size, input)
+	}
+	_, err := p.Parse([]string{"--size=1.5B"})
+	assert.EqualError(t, err, `--size: expected byte size but got "1.5B": size must be a whole number of bytes`)
+	_, err = p.Parse([]string{"--size=10XB"})
+	assert.EqualError(t, err, `--size: expected byte size but got "10XB": unknown unit "XB"`)
+	_, err = p.Parse([]string{"--small=1MiB"})
+	assert.EqualError(t, err, `--small: byte size "1MiB" overflows uint16`)
+}
+
+func TestHostPortMapper(t *testing.T) {
+	var cli struct {
+		Listen string `type:"hostport"`
+	}
+	p := mustNew(t, &cli)
+	_, err := p.Parse([]string{"--listen=example.com:443"})
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com:443", cli.Listen)
+	_, err = p.Parse([]string{"--listen=:8080"})
+	assert.NoError(t, err)
+	_, err = p.Parse([]string{"--listen=example.com:https"})
+	assert.EqualError(t, err, `--listen: expected host:port but got "example.com:https": invalid port "https"`)
+	_, err = p.Parse([]string{"--listen=example.com"})
+	assert.Error(t, err)
+}
+
+func TestExtendedMapperPlaceHolders(t *testing.T) {
+	var cli struct {
+		IP       net.IP
+		Prefix   netip.Prefix
+		Regex    *regexp.Regexp
+		Location *time.Location `default:"UTC"`
+		Size     int            `type:"bytesize"`
+		Listen   string         `type:"hostport" placeholder:"ADDR"`
+	}
+	b := bytes.NewBuffer(nil)
+	p := mustNew(t, &cli, kong.Writers(b, b), kong.Exit(func(int) { panic("exit") }))
+	assert.Panics(t, func() {
+		_, err := p.Parse([]string{"--help"})
+		assert.NoError(t, err)
+	})
+	for _, expected := range []string{"--ip=IP", "--prefix=CIDR", "--regex=REGEX", "--location=UTC", "--size=SIZE", "--listen=ADDR"} {
+		assert.Contains(t, b.String(), expected)
+	}
+}
+
+type outputFileCLI struct {
+	Force  bool
+	Output kong.OutputFile `force:"force" perm:"0600"`
+	Temp   kong.OutputFile `atomic:""`
+	Writer io.Writer       `type:"outputfile"`
+}
+
+func (c *outputFileCLI) Run() error {
+	writers := []io.Writer{}
+	for _, out := range []*kong.OutputFile{&c.Output, &c.Temp} {
+		if out.Path != "" {
+			writers = append(writers, out)
+		}
+	}
+	if c.Writer != nil {
+		writers = append(writers, c.Writer)
+	}
+	for _, w := range writers {
+		if _, err := fmt.Fprint(w, "hello"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestOutputFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.txt")
+
+	var cli outputFileCLI
+	ctx, err := mustNew(t, &cli).Parse([]string{"--output", path})
+	assert.NoError(t, err)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "file should be created lazily")
+	assert.NoError(t, ctx.Run())
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	cli = outputFileCLI{}
+	_, err = mustNew(t, &cli).Parse([]string{"--output", path})
+	assert.EqualError(t, err, path+" already exists, use --force to overwrite it")
+
+	cli = outputFileCLI{}
+	ctx, err = mustNew(t, &cli).Parse([]string{"--output", path, "--force"})
+	assert.NoError(t, err)
+	assert.NoError(t, ctx.Run())
+
+	cli = outputFileCLI{}
+	_, err = mustNew(t, &cli).Parse([]string{"--temp", path})
+	assert.EqualError(t, err, path+" already exists")
+}
+
+func TestOutputFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.txt")
+	var cli outputFileCLI
+	ctx, err := mustNew(t, &cli).Parse([]string{"--temp", path})
+	assert.NoError(t, err)
+	_, err = cli.Temp.Write([]byte("partial "))
+	assert.NoError(t, err)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "atomic file should not exist until closed")
+	assert.NoError(t, ctx.Run())
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "partial hello", string(data))
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(entries))
+}
+
+type failingOutputCmd struct {
+	Out kong.OutputFile `arg:"" atomic:""`
+}
+
+func (f *failingOutputCmd) Run() error {
+	if _, err := fmt.Fprint(&f.Out, "partial"); err != nil {
+		return err
+	}
+	return errors.New("failed")
+}
+
+func TestOutputFileAtomicRunError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.txt")
+	var cli failingOutputCmd
+	ctx, err := mustNew(t, &cli).Parse([]string{path})
+	assert.NoError(t, err)
+	assert.EqualError(t, ctx.Run(), "failed")
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "destination should not be created")
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(entries))
+	assert.NoError(t, cli.Out.Close())
+}
+
+type sequenceOutputCmd struct{}
+
+func (sequenceOutputCmd) Run(ctx *kong.Context, out *kong.OutputFile) error {
+	_, err := fmt.Fprintln(out, ctx.Command())
+	return err
+}
+
+func TestOutputFileCommandSequence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.txt")
+	var cli struct {
+		Out kong.OutputFile   `atomic:""`
+		A   sequenceOutputCmd `cmd:""`
+		B   sequenceOutputCmd `cmd:""`
+	}
+	ctx, err := mustNew(t, &cli, kong.CommandSequence("")).Parse([]string{"--out", path, "a", "b"})
+	assert.NoError(t, err)
+	assert.NoError(t, ctx.Run(&cli.Out))
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "a\nb\n", string(data))
+}
+
+func TestOutputFileStdout(t *testing.T) {
+	var cli outputFileCLI
+	b := bytes.NewBuffer(nil)
+	ctx, err := mustNew(t, &cli, kong.Writers(b, b)).Parse([]string{"--output=-", "--writer=-"})
+	assert.NoError(t, err)
+	assert.True(t, cli.Output.IsStdout())
+	assert.NoError(t, ctx.Run())
+	assert.Equal(t, "hellohello", b.String())
+}
+
+func TestGlobMapper(t *testing.T) {
+	dir := t.TempDir()
+	for _, file := range []string{"a.go", "b.txt", "sub/c.go", "sub/deep/d.go", "vendor/e.go", "sub/c_test.go"} {
+		path := filepath.Join(dir, file)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		assert.NoError(t, os.WriteFile(path, nil, 0o600))
+	}
+	abs := func(files ...string) []string {
+		out := []string{}
+		for _, file := range files {
+			out = append(out, filepath.Join(dir, filepath.FromSlash(file)))
+		}
+		return out
+	}
+
+	var cli struct {
+		Files   []string `arg:"" optional:"" type:"glob" exclude:"vendor,*_test.go"`
+		Sources []string `type:"existingglob"`
+		All     []string `type:"glob"`
+	}
+	p := mustNew(t, &cli)
+	_, err := p.Parse([]string{filepath.Join(dir, "**/*.go"), filepath.Join(dir, "*.go")})
+	assert.NoError(t, err)
+	assert.Equal(t, abs("a.go", "sub/c.go", "sub/deep/d.go"), cli.Files)
+
+	_, err = p.Parse([]string{"--sources", filepath.Join(dir, "sub", "*.go") + "," + filepath.Join(dir, "b.txt")})
+	assert.NoError(t, err)
+	assert.Equal(t, abs("b.txt", "sub/c.go", "sub/c_test.go"), cli.Sources)
+
+	_, err = p.Parse([]string{"--all", filepath.Join(dir, "sub", "...")})
+	assert.NoError(t, err)
+	assert.Equal(t, abs("sub/c.go", "sub/c_test.go", "sub/deep/d.go"), cli.All)
+
+	_, err = p.Parse([]string{"--all", filepath.Join(dir, "*.rs")})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(cli.All))
+
+	missing := filepath.Join(dir, "*.rs")
+	_, err = p.Parse([]string{"--sources", missing})
+	assert.EqualError(t, err, fmt.Sprintf("--sources: no files match %q", missing))
+
+	var required struct {
+		Files []string `arg:"" type:"glob"`
+	}
+	_, err = mustNew(t, &required).Parse([]string{missing})
+	assert.EqualError(t, err, fmt.Sprintf("<files> ...: no files match %q", missing))
+
+	// Relative patterns give relative paths.
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.NoError(t, os.Chdir(dir))
+	defer os.Chdir(wd) //nolint:errcheck
+	_, err = p.Parse([]string{"--all", "./sub/...", "--all", "*.txt"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.FromSlash("./sub/c.go"), filepath.FromSlash("./sub/c_test.go"), filepath.FromSlash("./sub/deep/d.go"), "b.txt"}, cli.All)
+
+	// Unreadable directories are skipped.
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "locked"), 0o000))
+	defer os.Chmod(filepath.Join(dir, "locked"), 0o700) //nolint:errcheck
+	_, err = p.Parse([]string{"--all", "**/*.txt"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b.txt"}, cli.All)
+
+	var invalid struct {
+		Files string `type:"glob"`
+	}
+	_, err = mustNew(t, &invalid).Parse([]string{"--files=*"})
+	assert.EqualError(t, err, `--files: "glob" type must be applied to a []string not string`)
+}
+
+type logLevel int
+
+func parseLogLevel(s string) (logLevel, error) {
+	switch s {
+	case "debug":
+		return 0, nil
+	case "info":
+		return 1, nil
+	case "error":
+		return 2, nil
+	}
+	return 0, fmt.Errorf("unknown level %q", s)
+}
+
+func TestBindMapper(t *testing.T) {
+	var cli struct {
+		Level   logLevel
+		Levels  []logLevel
+		Ptr     *logLevel
+		ByName  map[string]logLevel
+		Default logLevel `default:"info"`
+	}
+	b := bytes.NewBuffer(nil)
+	p := mustNew(t, &cli, kong.BindMapper(parseLogLevel), kong.Writers(b, b), kong.Exit(func(int) { panic("exit") }))
+	_, err := p.Parse([]string{"--level=error", "--levels=debug,info", "--ptr=info", "--by-name=a=error"})
+	assert.NoError(t, err)
+	level := logLevel(1)
+	assert.Equal(t, logLevel(2), cli.Level)
+	assert.Equal(t, []logLevel{0, 1}, cli.Levels)
+	assert.Equal(t, &level, cli.Ptr)
+	assert.Equal(t, map[string]logLevel{"a": 2}, cli.ByName)
+	assert.Equal(t, logLevel(1), cli.Default)
+
+	_, err = p.Parse([]string{"--level=trace"})
+	assert.EqualError(t, err, `--level: expected log-level but got "trace": unknown level "trace"`)
+
+	assert.Panics(t, func() {
+		_, err := p.Parse([]string{"--help"})
+		assert.NoError(t, err)
+	})
+	assert.Contains(t, b.String(), "--level=LOG-LEVEL")
+	assert.Contains(t, b.String(), "--ptr=LOG-LEVEL")
+	assert.Contains(t, b.String(), "--default=info")
+}
+
+func TestTypeMapperFunc(t *testing.T) {
+	var cli struct {
+		Level logLevel `type:"level"`
+	}
+	_, err := mustNew(t, &cli, kong.NamedMapper("level", kong.TypeMapperFunc(parseLogLevel))).Parse([]string{"--level=info"})
+	assert.NoError(t, err)
+	assert.Equal(t, logLevel(1), cli.Level)
+}
+
+func TestDecodeContextPopString(t *testing.T) {
+	var named struct {
+		Level int `type:"level"`
+	}
+	_, err := mustNew(t, &named, kong.NamedMapper("level", kong.MapperFunc(func(ctx *kong.DecodeContext, target reflect.Value) error {
+		value, err := ctx.PopString("level")
+		if err != nil {
+			return err
+		}
+		level, err := parseLogLevel(value)
+		target.SetInt(int64(level))
+		return err
+	}))).Parse([]string{"--level=error"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, named.Level)
+}
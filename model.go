@@ -2,9 +2,11 @@ package kong
 
 import (
 	"fmt"
+	"io"
 	"math"
 	"os"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -56,7 +58,13 @@ type Node struct {
 	Passthrough bool // Set to true to stop flag parsing when encountered.
 	Active      bool // Denotes the node is part of an active branch in the CLI.
 
+	DefaultFunc DefaultCommandFunc // Dynamically selects a default command, see DynamicDefaultCommand.
+	DefaultHelp string             // Describes the command selected by DefaultFunc in help.
+
 	Argument *Value // Populated when Type is ArgumentNode.
+
+	lazy     func() error // Builds the rest of the node, see LazyCommands.
+	lazyHelp func()       // Sets the help of the node when it is first displayed.
 }
 
 func (*Node) node() {}
@@ -91,24 +99,57 @@ func (n *Node) findNode(key reflect.Value) *Node {
 
 // AllFlags returns flags from all ancestor branches encountered.
 //
+// Flags of ancestors are omitted if they are not inherited by n, see the "nopropagate" and
+// "inherit" tags.
+//
 // If "hide" is true hidden flags will be omitted.
 func (n *Node) AllFlags(hide bool) (out [][]*Flag) {
-	if n.Parent != nil {
-		out = append(out, n.Parent.AllFlags(hide)...)
-	}
-	group := []*Flag{}
-	for _, flag := range n.Flags {
-		if !hide || !flag.Hidden {
-			flag.Active = true
-			group = append(group, flag)
+	for node := n; node != nil; node = node.Parent {
+		group := []*Flag{}
+		for _, flag := range node.Flags {
+			if (!hide || !flag.Hidden) && n.inherits(node, flag) {
+				flag.Active = true
+				group = append(group, flag)
+			}
+		}
+		if len(group) > 0 {
+			out = append([][]*Flag{group}, out...)
 		}
-	}
-	if len(group) > 0 {
-		out = append(out, group)
 	}
 	return
 }
 
+// inherits returns true if flag, declared on n or one of its ancestors, applies to n.
+//
+// The help flag and global flags are always inherited.
+func (n *Node) inherits(owner *Node, flag *Flag) bool {
+	if owner == n || flag.Tag.Global {
+		return true
+	}
+	if _, ok := flag.Target.Interface().(helpFlag); ok {
+		return true
+	}
+	if flag.Tag.NoPropagate {
+		return false
+	}
+	for node := n; node != nil && node != owner; node = node.Parent {
+		if node.Tag == nil || node.Tag.Inherit == nil {
+			continue
+		}
+		inherited := false
+		for _, name := range node.Tag.Inherit {
+			if name == flag.Name {
+				inherited = true
+				break
+			}
+		}
+		if !inherited {
+			return false
+		}
+	}
+	return true
+}
+
 // Leaves returns the leaf commands/arguments under Node.
 //
 // If "hidden" is true hidden leaves will be omitted.
@@ -178,7 +219,11 @@ func (n *Node) Summary() string {
 	}
 	allFlags := n.Flags
 	if n.Parent != nil {
-		allFlags = append(allFlags, n.Parent.Flags...)
+		for _, flag := range n.Parent.Flags {
+			if n.inherits(n.Parent, flag) {
+				allFlags = append(allFlags, flag)
+			}
+		}
 	}
 	for _, flag := range allFlags {
 		if _, ok := flag.Target.Interface().(helpFlag); ok {
@@ -362,10 +407,28 @@ func (v *Value) IsCounter() bool {
 
 // Parse tokens into value, parse, and validate, but do not write to the field.
 func (v *Value) Parse(scan *Scanner, target reflect.Value) (err error) {
+	return v.parse(nil, scan, target, nil)
+}
+
+// parse is Parse with the configuration of the parser k, if not nil. seenKeys holds the map keys
+// already decoded into target by this parse, for "nodupes", or is nil if there are none.
+func (v *Value) parse(k *Kong, scan *Scanner, target reflect.Value, seenKeys map[any]bool) (err error) {
 	if target.Kind() == reflect.Ptr && target.IsNil() {
 		target.Set(reflect.New(target.Type().Elem()))
 	}
-	err = v.Mapper.Decode(&DecodeContext{Value: v, Scan: scan}, target)
+	if v.Tag != nil && v.Tag.FromFile {
+		if err = v.readFromFile(k, scan); err != nil {
+			return fmt.Errorf("%s: %w", v.ShortSummary(), err)
+		}
+	}
+	ctx := &DecodeContext{Value: v, Scan: scan, kong: k, seenKeys: seenKeys}
+	err = v.Mapper.Decode(ctx, target)
+	if err == nil {
+		err = v.normaliseSlice(reflect.Indirect(target))
+	}
+	if err == nil {
+		err = v.checkMapKeys(ctx.suggestOptions(), reflect.Indirect(target))
+	}
 	if err != nil {
 		return fmt.Errorf("%s: %w", v.ShortSummary(), err)
 	}
@@ -373,6 +436,158 @@ func (v *Value) Parse(scan *Scanner, target reflect.Value) (err error) {
 	return nil
 }
 
+// normaliseSlice applies the "unique", "sorted" and "maxitems" tags to a slice target.
+func (v *Value) normaliseSlice(target reflect.Value) error {
+	if v.Tag == nil || target.Kind() != reflect.Slice {
+		return nil
+	}
+	if v.Tag.Unique {
+		out := reflect.MakeSlice(target.Type(), 0, target.Len())
+	next:
+		for i := 0; i < target.Len(); i++ {
+			for j := 0; j < out.Len(); j++ {
+				if reflect.DeepEqual(target.Index(i).Interface(), out.Index(j).Interface()) {
+					continue next
+				}
+			}
+			out = reflect.Append(out, target.Index(i))
+		}
+		target.Set(out)
+	}
+	if v.Tag.Sorted {
+		sort.SliceStable(target.Interface(), v.sliceLess(target))
+	}
+	if v.Tag.MaxItems != 0 && target.Len() > v.Tag.MaxItems {
+		return fmt.Errorf("expected at most %d values but got %d", v.Tag.MaxItems, target.Len())
+	}
+	return nil
+}
+
+// KeyEnumSlice returns a slice of the valid map keys of this value, if any.
+func (v *Value) KeyEnumSlice() []string {
+	if v.Tag == nil || v.Tag.KeyEnum == "" {
+		return nil
+	}
+	parts := strings.Split(v.Tag.KeyEnum, ",")
+	out := make([]string, len(parts))
+	for i, part := range parts {
+		out[i] = strings.TrimSpace(part)
+	}
+	return out
+}
+
+// checkMapKeys applies the "keyenum" and "keyformat" tags to the keys of a map target.
+func (v *Value) checkMapKeys(suggest SuggestOptions, target reflect.Value) error {
+	if v.Tag == nil || target.Kind() != reflect.Map || (v.Tag.KeyEnum == "" && v.Tag.keyFormat == nil) {
+		return nil
+	}
+	keys := []string{}
+	for _, key := range target.MapKeys() {
+		keys = append(keys, fmt.Sprint(key.Interface()))
+	}
+	sort.Strings(keys)
+	enums := v.KeyEnumSlice()
+	for _, key := range keys {
+		if enums != nil {
+			found := false
+			for _, enum := range enums {
+				found = found || enum == key
+			}
+			if !found {
+				quoted := make([]string, len(enums))
+				for i, enum := range enums {
+					quoted[i] = strconv.Quote(enum)
+				}
+				return findPotentialCandidates(suggest, key, enums,
+					"map key must be one of %s but got %q", strings.Join(quoted, ","), key)
+			}
+		}
+		if v.Tag.keyFormat != nil && !v.Tag.keyFormat.MatchString(key) {
+			return fmt.Errorf("map key %q does not match %q", key, v.Tag.KeyFormat)
+		}
+	}
+	return nil
+}
+
+// sliceLess orders the elements of a slice target by their position in the enum, if any, or by
+// their natural order otherwise.
+func (v *Value) sliceLess(target reflect.Value) func(i, j int) bool {
+	if v.Enum != "" {
+		fold := func(s string) string { return s }
+		if v.Tag.EnumFold {
+			fold = strings.ToLower
+		}
+		order := map[string]int{}
+		for i, enum := range v.EnumSlice() {
+			order[fold(enum)] = i
+		}
+		values, isEnum := enumValues(target.Type().Elem())
+		key := func(el reflect.Value) int {
+			if isEnum {
+				if name, ok := enumName(values, el); ok {
+					return order[name]
+				}
+			}
+			return order[fold(fmt.Sprint(reflect.Indirect(el).Interface()))]
+		}
+		return func(i, j int) bool { return key(target.Index(i)) < key(target.Index(j)) }
+	}
+	return func(i, j int) bool {
+		a, b := reflect.Indirect(target.Index(i)), reflect.Indirect(target.Index(j))
+		switch a.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return a.Int() < b.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return a.Uint() < b.Uint()
+		case reflect.Float32, reflect.Float64:
+			return a.Float() < b.Float()
+		case reflect.String:
+			return a.String() < b.String()
+		default:
+			return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+		}
+	}
+}
+
+// readFromFile replaces a next value of "-" or "@path" with the contents of stdin or the file
+// at path, minus a trailing newline. "@@" escapes a literal "@".
+func (v *Value) readFromFile(k *Kong, scan *Scanner) error {
+	token := scan.Peek()
+	value, ok := token.Value.(string)
+	if !ok || (v.IsBool() && token.Type != FlagValueToken) {
+		return nil
+	}
+	var (
+		data []byte
+		err  error
+	)
+	switch {
+	case value == "-":
+		var stdin io.Reader = os.Stdin
+		if k != nil {
+			stdin = k.stdin
+		}
+		if data, err = io.ReadAll(stdin); err != nil {
+			return fmt.Errorf("reading stdin: %w", err)
+		}
+
+	case strings.HasPrefix(value, "@@"):
+		data = []byte(value[1:])
+
+	case strings.HasPrefix(value, "@"):
+		if data, err = os.ReadFile(ExpandPath(value[1:])); err != nil {
+			return err
+		}
+
+	default:
+		return nil
+	}
+	scan.Pop()
+	value = strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+	scan.PushToken(Token{Type: token.Type, Value: value})
+	return nil
+}
+
 // Apply value to field.
 func (v *Value) Apply(value reflect.Value) {
 	v.Target.Set(value)
@@ -381,8 +596,12 @@ func (v *Value) Apply(value reflect.Value) {
 
 // ApplyDefault value to field if it is not already set.
 func (v *Value) ApplyDefault() error {
+	return v.applyDefault(nil)
+}
+
+func (v *Value) applyDefault(k *Kong) error {
 	if reflectValueIsZero(v.Target) {
-		return v.Reset()
+		return v.reset(k)
 	}
 	v.Set = true
 	return nil
@@ -393,13 +612,18 @@ func (v *Value) ApplyDefault() error {
 //
 // Does not include resolvers.
 func (v *Value) Reset() error {
+	return v.reset(nil)
+}
+
+// reset is Reset with the configuration of the parser k, if not nil.
+func (v *Value) reset(k *Kong) error {
 	v.Target.Set(reflect.Zero(v.Target.Type()))
 	if len(v.Tag.Envs) != 0 {
 		for _, env := range v.Tag.Envs {
 			envar, ok := os.LookupEnv(env)
 			// Parse the first non-empty ENV in the list
 			if ok {
-				err := v.Parse(ScanFromTokens(Token{Type: FlagValueToken, Value: envar}), v.Target)
+				err := v.parse(k, ScanFromTokens(Token{Type: FlagValueToken, Value: envar}), v.Target, nil)
 				if err != nil {
 					return fmt.Errorf("%s (from envar %s=%q)", err, env, envar)
 				}
@@ -408,7 +632,7 @@ func (v *Value) Reset() error {
 		}
 	}
 	if v.HasDefault {
-		return v.Parse(ScanFromTokens(Token{Type: FlagValueToken, Value: v.Default}), v.Target)
+		return v.parse(k, ScanFromTokens(Token{Type: FlagValueToken, Value: v.Default}), v.Target, nil)
 	}
 	return nil
 }
@@ -445,7 +669,12 @@ func (f *Flag) String() string {
 
 // FormatPlaceHolder formats the placeholder string for a Flag.
 func (f *Flag) FormatPlaceHolder() string {
-	placeholderHelper, ok := f.Value.Mapper.(PlaceHolderProvider)
+	mapper := f.Value.Mapper
+	// Pointers use the placeholder of their element's mapper, if any.
+	if ptr, ok := mapper.(ptrMapper); ok && f.Target.Kind() == reflect.Ptr {
+		mapper = ptr.r.ForType(f.Target.Type().Elem())
+	}
+	placeholderHelper, ok := mapper.(PlaceHolderProvider)
 	if ok {
 		return placeholderHelper.PlaceHolder(f)
 	}
@@ -462,10 +691,19 @@ func (f *Flag) FormatPlaceHolder() string {
 		}
 		return f.Default + tail
 	}
+	if typed, ok := mapper.(typePlaceHolderProvider); ok {
+		return typed.typePlaceHolder(f) + tail
+	}
+	if f.Value.IsSlice() && f.Enum != "" {
+		return "{" + strings.Join(f.EnumSlice(), ",") + "}..."
+	}
 	if f.Value.IsMap() {
 		if f.Value.Tag.MapSep != -1 && f.Tag.Type == "" {
 			tail = string(f.Value.Tag.MapSep) + "..."
 		}
+		if keys := f.KeyEnumSlice(); keys != nil {
+			return "{" + strings.Join(keys, ",") + "}=VALUE" + tail
+		}
 		return "KEY=VALUE" + tail
 	}
 	if f.Tag != nil && f.Tag.TypeName != "" {
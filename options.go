@@ -10,6 +10,7 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
+	"time"
 )
 
 // An Option applies optional changes to the Kong application.
@@ -65,6 +66,17 @@ func WithHyphenPrefixedParameters(enable bool) Option {
 	})
 }
 
+// CaseInsensitive makes long flag names, command names and their aliases case-insensitive.
+//
+// Names that differ only by case are rejected when the grammar is built. Short flags remain
+// case-sensitive. Use the "enumfold" tag to also match enum values case-insensitively.
+func CaseInsensitive() Option {
+	return OptionFunc(func(k *Kong) error {
+		k.caseInsensitive = true
+		return nil
+	})
+}
+
 type embedded struct {
 	strct any
 	tags  []string
@@ -96,6 +108,9 @@ type dynamicCommand struct {
 //
 // This is useful for command-line structures that are extensible via user-provided plugins.
 //
+// "cmd" is either a pointer to a struct or a *CommandBuilder, whose name, help and group are
+// replaced by those given.
+//
 // "tags" is a list of extra tag strings to parse, in the form <key>:"<value>".
 func DynamicCommand(name, help, group string, cmd any, tags ...string) Option {
 	return OptionFunc(func(k *Kong) error {
@@ -110,6 +125,66 @@ func DynamicCommand(name, help, group string, cmd any, tags ...string) Option {
 	})
 }
 
+// DefaultCommandFunc selects the command to run among the children of node when none of them is
+// named on the command-line, given the remaining arguments. It returns nil to select none, in
+// which case any static default command is used.
+type DefaultCommandFunc func(ctx *Context, node *Node, args []string) (*Node, error)
+
+// DynamicDefaultCommand dynamically selects the default sub-command of the command at path, eg.
+// "remote", or "" for the application, with fn. Unlike the "default" tag, the selected command
+// may have arguments, which are parsed from the remaining arguments.
+//
+// "help" describes the selection, eg. `If the first argument is a file, "open" is run.`, and is
+// displayed after the commands in help.
+func DynamicDefaultCommand(path, help string, fn DefaultCommandFunc) Option {
+	return PostBuild(func(k *Kong) error {
+		node, err := findCommand(k.Model.Node, path)
+		if err != nil {
+			return fmt.Errorf("DynamicDefaultCommand: %w", err)
+		}
+		node.DefaultFunc = fn
+		node.DefaultHelp = help
+		return nil
+	})
+}
+
+// findCommand returns the command at path under node, where path is a space separated list of
+// command names, building any lazily built commands on the way.
+func findCommand(node *Node, path string) (*Node, error) {
+	for _, name := range strings.Fields(path) {
+		var child *Node
+		for _, candidate := range node.Children {
+			if candidate.Type == CommandNode && candidate.Name == name {
+				child = candidate
+				break
+			}
+		}
+		if child == nil {
+			return nil, fmt.Errorf("unknown command %q", path)
+		}
+		if err := child.buildLazy(); err != nil {
+			return nil, err
+		}
+		node = child
+	}
+	return node, nil
+}
+
+// Commands registers commands built with NewCommand with the root of the CLI.
+func Commands(cmds ...*CommandBuilder) Option {
+	return OptionFunc(func(k *Kong) error {
+		for _, cmd := range cmds {
+			k.dynamicCommands = append(k.dynamicCommands, &dynamicCommand{
+				name:  cmd.name,
+				help:  cmd.help,
+				group: cmd.group,
+				cmd:   cmd,
+			})
+		}
+		return nil
+	})
+}
+
 // NoDefaultHelp disables the default help flags.
 func NoDefaultHelp() Option {
 	return OptionFunc(func(k *Kong) error {
@@ -187,6 +262,15 @@ func TypeMapper(typ reflect.Type, mapper Mapper) Option {
 	})
 }
 
+// BindMapper registers a Mapper for values of type T that decodes them using parse, eg.
+//
+//	kong.BindMapper(ParseColour)
+//
+// See TypeMapperFunc for details.
+func BindMapper[T any](parse func(string) (T, error)) Option {
+	return TypeMapper(reflect.TypeOf((*T)(nil)).Elem(), TypeMapperFunc(parse))
+}
+
 // KindMapper registers a mapper to a kind.
 func KindMapper(kind reflect.Kind, mapper Mapper) Option {
 	return OptionFunc(func(k *Kong) error {
@@ -211,6 +295,16 @@ func NamedMapper(name string, mapper Mapper) Option {
 	})
 }
 
+// ExtendedDurations allows time.Duration values to include days (d) and weeks (w), eg. 1w2d or 1.5d.
+//
+// A day is always 24 hours.
+func ExtendedDurations() Option {
+	return OptionFunc(func(k *Kong) error {
+		k.registry.RegisterType(reflect.TypeOf(time.Duration(0)), durationDecoder(true))
+		return nil
+	})
+}
+
 // Writers overrides the default writers. Useful for testing or interactive use.
 func Writers(stdout, stderr io.Writer) Option {
 	return OptionFunc(func(k *Kong) error {
@@ -220,6 +314,14 @@ func Writers(stdout, stderr io.Writer) Option {
 	})
 }
 
+// Stdin overrides the reader used by "fromfile" flags for "-". Defaults to os.Stdin.
+func Stdin(stdin io.Reader) Option {
+	return OptionFunc(func(k *Kong) error {
+		k.stdin = stdin
+		return nil
+	})
+}
+
 // Bind binds values for hooks and Run() function arguments.
 //
 // Any arguments passed will be available to the receiving hook functions, but may be omitted. Additionally, *Kong and
@@ -334,9 +436,9 @@ func ConfigureHelp(options HelpOptions) Option {
 
 // AutoGroup automatically assigns groups to flags.
 func AutoGroup(format func(parent Visitable, flag *Flag) *Group) Option {
-	return PostBuild(func(kong *Kong) error {
-		parents := []Visitable{kong.Model}
-		return Visit(kong.Model, func(node Visitable, next Next) error {
+	group := func(parent, root Visitable) error {
+		parents := []Visitable{parent}
+		return Visit(root, func(node Visitable, next Next) error {
 			if flag, ok := node.(*Flag); ok && flag.Group == nil {
 				flag.Group = format(parents[len(parents)-1], flag)
 			}
@@ -344,6 +446,12 @@ func AutoGroup(format func(parent Visitable, flag *Flag) *Group) Option {
 			defer func() { parents = parents[:len(parents)-1] }()
 			return next(nil)
 		})
+	}
+	return PostBuild(func(kong *Kong) error {
+		kong.onLazyBuild = append(kong.onLazyBuild, func(node *Node) error {
+			return group(node.Parent, node)
+		})
+		return group(kong.Model, kong.Model)
 	})
 }
 
@@ -553,6 +661,10 @@ func DefaultEnvars(prefix string) Option {
 	}
 
 	return PostBuild(func(k *Kong) error {
+		k.onLazyBuild = append(k.onLazyBuild, func(node *Node) error {
+			processNode(node)
+			return nil
+		})
 		processNode(k.Model.Node)
 		return nil
 	})
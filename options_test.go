@@ -1,7 +1,10 @@
 package kong
 
 import (
+	"bytes"
 	"context"
+	"io"
+	"os/exec"
 	"reflect"
 	"strings"
 	"testing"
@@ -201,3 +204,61 @@ func TestCallbackNonPointerError(t *testing.T) {
 	err = callFunction(reflect.ValueOf(method), p.bindings)
 	assert.EqualError(t, err, "ERROR: failed")
 }
+
+func TestHelpPager(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	var cli struct {
+		Flag string `help:"A flag."`
+	}
+	newParser := func(t *testing.T, height int, command ...string) (*Kong, *bytes.Buffer) {
+		t.Helper()
+		w := &bytes.Buffer{}
+		p, err := New(&cli, Name("test"), Writers(w, w), Exit(func(int) {}), HelpPager(command...))
+		assert.NoError(t, err)
+		p.pager.height = func(io.Writer) int { return height }
+		return p, w
+	}
+	expected := `Usage: test [flags]
+
+Flags:
+  -h, --help           Show context-sensitive help.
+      --flag=STRING    A flag.
+`
+
+	t.Run("Paged", func(t *testing.T) {
+		p, w := newParser(t, 2, "sh", "-c", "echo paged; cat")
+		_, err := p.Parse([]string{"--help"})
+		assert.NoError(t, err)
+		assert.Equal(t, "paged\n"+expected, w.String())
+	})
+
+	t.Run("FitsOnTerminal", func(t *testing.T) {
+		p, w := newParser(t, 100, "sh", "-c", "echo paged; cat")
+		_, err := p.Parse([]string{"--help"})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, w.String())
+	})
+
+	t.Run("NotATerminal", func(t *testing.T) {
+		p, w := newParser(t, 0, "sh", "-c", "echo paged; cat")
+		_, err := p.Parse([]string{"--help"})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, w.String())
+	})
+
+	t.Run("MissingPager", func(t *testing.T) {
+		p, w := newParser(t, 2, "kong-missing-pager")
+		_, err := p.Parse([]string{"--help"})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, w.String())
+	})
+
+	t.Run("EnvPager", func(t *testing.T) {
+		t.Setenv("PAGER", "more -s")
+		assert.Equal(t, []string{"more", "-s"}, (&helpPager{}).Command())
+		t.Setenv("PAGER", "")
+		assert.Equal(t, []string{"less", "-R"}, (&helpPager{}).Command())
+	})
+}
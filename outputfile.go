@@ -0,0 +1,232 @@
+package kong
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+)
+
+// OutputFile is a flag or argument value for a file to write to. "-" writes to Kong's stdout.
+//
+// The file is not created until the first Write, and is closed by an AfterRun hook once every
+// command of a command sequence has run. If Run() fails, an atomic file is discarded instead. It
+// can be configured with the following tags:
+//
+//	perm:"0600"     Permissions of a created file. Defaults to 0666 before umask, or 0600 for
+//	                atomic files.
+//	atomic:""       Write to a temporary file that is renamed over the path on Close, or removed
+//	                by Discard.
+//	force:"<flag>"  Name of a boolean flag that allows overwriting an existing file. Without it,
+//	                existing files are never overwritten.
+type OutputFile struct {
+	// Path to the file, or "-" for stdout.
+	Path string
+
+	perm      os.FileMode
+	atomic    bool
+	force     string
+	overwrite bool
+	stdout    io.Writer
+	file      *os.File
+	closed    bool
+}
+
+var _ io.WriteCloser = (*OutputFile)(nil)
+
+// IsStdout returns true if the output is Kong's stdout.
+func (o *OutputFile) IsStdout() bool { return o.Path == "-" }
+
+func (o *OutputFile) String() string { return o.Path }
+
+func (o *OutputFile) Decode(ctx *DecodeContext) error { //nolint: revive
+	var path string
+	if err := ctx.Scan.PopValueInto("filename", &path); err != nil {
+		return err
+	}
+	*o = OutputFile{Path: path}
+	if path != "-" {
+		o.Path = ExpandPath(path)
+	}
+	if tag := ctx.Value.Tag; tag != nil {
+		if perm := tag.Get("perm"); perm != "" {
+			n, err := strconv.ParseUint(perm, 8, 32)
+			if err != nil {
+				return fmt.Errorf("invalid perm %q: %w", perm, err)
+			}
+			o.perm = os.FileMode(n)
+		}
+		o.atomic = tag.Has("atomic")
+		o.force = tag.Get("force")
+	}
+	return nil
+}
+
+// AfterApply resolves the force flag and refuses to overwrite an existing file without it.
+func (o *OutputFile) AfterApply(ctx *Context) error {
+	o.stdout = ctx.Stdout
+	if o.Path == "" || o.IsStdout() {
+		return nil
+	}
+	if o.force != "" {
+		var flag *Flag
+		for _, f := range ctx.Flags() {
+			if f.Name == o.force {
+				flag = f
+			}
+		}
+		if flag == nil {
+			return fmt.Errorf("%s: unknown force flag --%s", o.Path, o.force)
+		}
+		overwrite, ok := ctx.FlagValue(flag).(bool)
+		if !ok {
+			return fmt.Errorf("%s: force flag --%s must be a bool", o.Path, o.force)
+		}
+		o.overwrite = overwrite
+	}
+	return o.checkOverwrite()
+}
+
+// AfterRun closes the file, or discards it if Run() failed.
+func (o *OutputFile) AfterRun(ctx *Context) error {
+	if ctx.runErr != nil {
+		return o.Discard()
+	}
+	return o.Close()
+}
+
+// Write to the file, creating it if necessary.
+func (o *OutputFile) Write(p []byte) (int, error) {
+	if o.Path == "" {
+		return 0, errors.New("output file not set")
+	}
+	if o.closed {
+		return 0, fmt.Errorf("%s: %w", o.Path, os.ErrClosed)
+	}
+	if o.IsStdout() {
+		if o.stdout == nil {
+			return os.Stdout.Write(p)
+		}
+		return o.stdout.Write(p)
+	}
+	if o.file == nil {
+		if err := o.create(); err != nil {
+			return 0, err
+		}
+	}
+	return o.file.Write(p)
+}
+
+// Close the file. If the file is atomic, it is renamed over the path.
+//
+// Closing stdout, a file that was never written to or a closed file is a no-op.
+func (o *OutputFile) Close() error {
+	return o.close(true)
+}
+
+// Discard closes the file. If the file is atomic, it is removed and the path is left untouched.
+//
+// Discarding stdout, a file that was never written to or a closed file is a no-op.
+func (o *OutputFile) Discard() error {
+	return o.close(false)
+}
+
+func (o *OutputFile) close(keep bool) error {
+	if o.closed || o.file == nil {
+		o.closed = true
+		return nil
+	}
+	o.closed = true
+	err := o.file.Close()
+	if !o.atomic {
+		return err
+	}
+	tmp := o.file.Name()
+	if !keep {
+		return errors.Join(err, os.Remove(tmp))
+	}
+	if err == nil {
+		err = o.checkOverwrite()
+	}
+	if err == nil {
+		err = os.Rename(tmp, o.Path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+	}
+	return err
+}
+
+func (o *OutputFile) create() error {
+	if !o.atomic {
+		flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		if !o.overwrite {
+			flags |= os.O_EXCL
+		}
+		perm := o.perm
+		if perm == 0 {
+			perm = 0o666
+		}
+		file, err := os.OpenFile(o.Path, flags, perm) //nolint: gosec
+		if errors.Is(err, os.ErrExist) {
+			if cerr := o.checkOverwrite(); cerr != nil {
+				return cerr
+			}
+		}
+		if err != nil {
+			return err
+		}
+		o.file = file
+		return nil
+	}
+	if err := o.checkOverwrite(); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(o.Path), "."+filepath.Base(o.Path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if o.perm != 0 {
+		if err := file.Chmod(o.perm); err != nil {
+			_ = file.Close()
+			_ = os.Remove(file.Name())
+			return err
+		}
+	}
+	o.file = file
+	return nil
+}
+
+func (o *OutputFile) checkOverwrite() error {
+	if o.overwrite {
+		return nil
+	}
+	if _, err := os.Stat(o.Path); err == nil {
+		if o.force != "" {
+			return fmt.Errorf("%s already exists, use --%s to overwrite it", o.Path, o.force)
+		}
+		return fmt.Errorf("%s already exists", o.Path)
+	}
+	return nil
+}
+
+func outputFileMapper() MapperFunc {
+	return func(ctx *DecodeContext, target reflect.Value) error {
+		out := &OutputFile{}
+		if err := out.Decode(ctx); err != nil {
+			return err
+		}
+		switch {
+		case target.Type() == reflect.TypeOf(OutputFile{}):
+			target.Set(reflect.ValueOf(out).Elem())
+		case reflect.TypeOf(out).AssignableTo(target.Type()):
+			target.Set(reflect.ValueOf(out))
+		default:
+			return fmt.Errorf("type:\"outputfile\" must be used with a kong.OutputFile or io.Writer field")
+		}
+		return nil
+	}
+}
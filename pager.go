@@ -0,0 +1,66 @@
+package kong
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// defaultPager is used when $PAGER is not set.
+var defaultPager = []string{"less", "-R"}
+
+// helpPager pipes help output through an external pager.
+type helpPager struct {
+	// Pager command and arguments. If empty, $PAGER or defaultPager is used.
+	command []string
+	// Returns the height of the terminal attached to a writer, or 0 if it is not a terminal.
+	height func(w io.Writer) int
+}
+
+// HelpPager pipes help output through a pager when Kong's Stdout is a terminal and
+// the help is taller than the terminal.
+//
+// "command" is the pager to run along with its arguments. If omitted, $PAGER is used,
+// falling back to "less -R". If the pager can not be started, help is written directly.
+func HelpPager(command ...string) Option {
+	return OptionFunc(func(k *Kong) error {
+		k.pager = &helpPager{command: command, height: guessHeight}
+		return nil
+	})
+}
+
+// Command returns the pager command to run.
+func (p *helpPager) Command() []string {
+	if len(p.command) > 0 {
+		return p.command
+	}
+	if pager := strings.Fields(os.Getenv("PAGER")); len(pager) > 0 {
+		return pager
+	}
+	return defaultPager
+}
+
+// Page writes text to w through the pager if it has at least as many lines as the terminal.
+//
+// Returns false if the text was not paged and should be written directly.
+func (p *helpPager) Page(w, stderr io.Writer, text string, lines int) (bool, error) {
+	height := p.height(w)
+	if height <= 0 || lines < height {
+		return false, nil
+	}
+	command := p.Command()
+	cmd := exec.Command(command[0], command[1:]...) //nolint:gosec // the pager is configured by the user
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout = w
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		var execErr *exec.Error
+		if errors.As(err, &execErr) || errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, cmd.Wait()
+}
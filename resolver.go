@@ -2,7 +2,10 @@ package kong
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"sort"
 	"strings"
 )
 
@@ -17,6 +20,13 @@ type Resolver interface {
 	Resolve(context *Context, parent *Path, flag *Flag) (any, error)
 }
 
+// suggestingValidator is implemented by resolvers that suggest corrections for invalid
+// configuration, using the SuggestOptions of the application. keys are top-level keys read by the
+// application itself, eg. the key of aliases.
+type suggestingValidator interface {
+	validate(app *Application, suggest SuggestOptions, keys []string) error
+}
+
 // ResolverFunc is a convenience type for non-validating Resolvers.
 type ResolverFunc func(context *Context, parent *Path, flag *Flag) (any, error)
 
@@ -36,30 +46,116 @@ func JSON(r io.Reader) (Resolver, error) {
 	if err != nil {
 		return nil, err
 	}
-	var f ResolverFunc = func(context *Context, parent *Path, flag *Flag) (any, error) {
-		name := strings.ReplaceAll(flag.Name, "-", "_")
-		snakeCaseName := snakeCase(flag.Name)
-		raw, ok := values[name]
-		if ok {
-			return raw, nil
-		} else if raw, ok = values[snakeCaseName]; ok {
-			return raw, nil
-		}
-		raw = values
-		for _, part := range strings.Split(name, ".") {
-			if values, ok := raw.(map[string]any); ok {
-				raw, ok = values[part]
-				if !ok {
-					return nil, nil
-				}
-			} else {
+	return &jsonResolver{values: values}, nil
+}
+
+// StrictJSON returns a Resolver that retrieves values from a JSON source, like JSON, but
+// rejects keys that do not correspond to any flag, suggesting the closest matching keys.
+func StrictJSON(r io.Reader) (Resolver, error) {
+	resolver, err := JSON(r)
+	if err != nil {
+		return nil, err
+	}
+	resolver.(*jsonResolver).strict = true //nolint:forcetypeassert
+	return resolver, nil
+}
+
+var _ suggestingValidator = (*jsonResolver)(nil)
+
+type jsonResolver struct {
+	values map[string]any
+	strict bool
+}
+
+func (j *jsonResolver) Resolve(context *Context, parent *Path, flag *Flag) (any, error) {
+	name := strings.ReplaceAll(flag.Name, "-", "_")
+	snakeCaseName := snakeCase(flag.Name)
+	raw, ok := j.values[name]
+	if ok {
+		return raw, nil
+	} else if raw, ok = j.values[snakeCaseName]; ok {
+		return raw, nil
+	}
+	raw = j.values
+	for _, part := range strings.Split(name, ".") {
+		if values, ok := raw.(map[string]any); ok {
+			raw, ok = values[part]
+			if !ok {
 				return nil, nil
 			}
+		} else {
+			return nil, nil
 		}
-		return raw, nil
 	}
+	return raw, nil
+}
 
-	return f, nil
+// Aliases returns the aliases in the object key, if any.
+func (j *jsonResolver) Aliases(key string) (map[string]string, error) {
+	raw, ok := j.values[key].(map[string]any)
+	if !ok {
+		return nil, nil
+	}
+	aliases := map[string]string{}
+	for name, value := range raw {
+		expansion, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("alias %q must be a string but got %T", name, value)
+		}
+		aliases[name] = expansion
+	}
+	return aliases, nil
+}
+
+func (j *jsonResolver) Validate(app *Application) error {
+	return j.validate(app, DefaultSuggestOptions, nil)
+}
+
+// validate the keys of a strict resolver, suggesting the closest flags for unknown keys.
+func (j *jsonResolver) validate(app *Application, suggest SuggestOptions, keys []string) error {
+	if !j.strict {
+		return nil
+	}
+	// Flags of lazily built commands must be known.
+	if err := app.Node.Build(); err != nil {
+		return err
+	}
+	known := map[string]bool{}
+	for _, key := range keys {
+		known[key] = true
+	}
+	candidates := []string{}
+	_ = Visit(app, func(node Visitable, next Next) error {
+		if flag, ok := node.(*Flag); ok {
+			name := strings.ReplaceAll(flag.Name, "-", "_")
+			known[name] = true
+			known[snakeCase(flag.Name)] = true
+			candidates = append(candidates, name)
+		}
+		return next(nil)
+	})
+	unknown := []string{}
+	var walk func(prefix string, values map[string]any)
+	walk = func(prefix string, values map[string]any) {
+		for key, value := range values {
+			key = prefix + key
+			if known[key] {
+				continue
+			}
+			if nested, ok := value.(map[string]any); ok {
+				walk(key+".", nested)
+				continue
+			}
+			unknown = append(unknown, key)
+		}
+	}
+	walk("", j.values)
+	sort.Strings(unknown)
+	errs := []error{}
+	for _, key := range unknown {
+		errs = append(errs, fmt.Errorf("unknown configuration key %q%s", key, suggest.didYouMean(key, candidates)))
+	}
+	return errors.Join(errs...)
 }
 
 func snakeCase(name string) string {
@@ -320,6 +320,43 @@ func TestJSONBasic(t *testing.T) {
 	assert.True(t, cli.Bool)
 }
 
+func TestStrictJSON(t *testing.T) {
+	var cli struct {
+		LogLevel string
+		Labels   map[string]string
+		One      struct {
+			String string
+		} `prefix:"one." embed:""`
+	}
+
+	json := `{
+		"log_level": "info",
+		"labels": {"a": "b"},
+		"one": {"strng": "one value"},
+		"loglevle": "debug"
+	}`
+
+	r, err := kong.StrictJSON(strings.NewReader(json))
+	assert.NoError(t, err)
+
+	parser := mustNew(t, &cli, kong.Resolvers(r))
+	_, err = parser.Parse([]string{})
+	assert.EqualError(t, err, "unknown configuration key \"loglevle\", did you mean \"log_level\"?\n"+
+		"unknown configuration key \"one.strng\", did you mean \"one.string\"?")
+
+	r, err = kong.StrictJSON(strings.NewReader(`{"loglevle": "debug"}`))
+	assert.NoError(t, err)
+	parser = mustNew(t, &cli, kong.Resolvers(r), kong.SuggestOptions{})
+	_, err = parser.Parse([]string{})
+	assert.EqualError(t, err, `unknown configuration key "loglevle"`)
+
+	r, err = kong.StrictJSON(strings.NewReader(`{"log_level": "info", "logLevel": "info", "one.string": "x"}`))
+	assert.NoError(t, err)
+	parser = mustNew(t, &cli, kong.Resolvers(r))
+	_, err = parser.Parse([]string{})
+	assert.NoError(t, err)
+}
+
 type testUppercaseMapper struct{}
 
 func (testUppercaseMapper) Decode(ctx *kong.DecodeContext, target reflect.Value) error {
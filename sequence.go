@@ -0,0 +1,151 @@
+package kong
+
+import "fmt"
+
+// CommandSequenceOptions configures the parsing of several commands in one invocation, see
+// CommandSequence.
+type CommandSequenceOptions struct {
+	// Path of the command at which parsing restarts after each command, eg. "run", or "" for the
+	// application.
+	Path string
+	// Separator explicitly ending a command, eg. "+". It is required after commands with optional
+	// or cumulative positional arguments.
+	Separator string
+}
+
+// Apply the command sequence options once the model is built.
+func (o CommandSequenceOptions) Apply(k *Kong) error {
+	k.postBuildOptions = append(k.postBuildOptions, OptionFunc(func(k *Kong) error {
+		node, err := findCommand(k.Model.Node, o.Path)
+		if err != nil {
+			return fmt.Errorf("CommandSequence: %w", err)
+		}
+		if len(node.Children) == 0 {
+			return fmt.Errorf("CommandSequence: %q has no sub-commands", o.Path)
+		}
+		k.sequenceNode = node
+		k.sequenceSeparator = o.Separator
+		return nil
+	}))
+	return nil
+}
+
+// CommandSequence allows several commands to be given in one invocation, as with task runners,
+// eg. "tool build test --short lint".
+//
+// Once a command without sub-commands has all of its positional arguments, parsing restarts at
+// the application with the next argument, so each command only accepts its own flags and those of
+// its parents. "separator", if not empty, can also be given to end a command explicitly, eg.
+// "tool run a b + test".
+//
+// Context.Sequence returns a Context for each command, and Context.Run runs them in order before
+// calling AfterRun hooks once. As commands store their flags and arguments in the grammar, each
+// command may only be given once.
+//
+// Use CommandSequenceOptions to restart parsing at a sub-command instead.
+func CommandSequence(separator string) Option {
+	return CommandSequenceOptions{Separator: separator}
+}
+
+// Sequence returns a Context for each command on the command-line, in order, when the
+// CommandSequence option is used. Otherwise it returns only c.
+func (c *Context) Sequence() []*Context {
+	if len(c.sequence) == 0 {
+		return []*Context{c}
+	}
+	starts := append([]int{c.sequenceStart}, c.sequence...)
+	out := make([]*Context, 0, len(starts))
+	for i, start := range starts {
+		end := len(c.Path)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		sub := *c
+		sub.Path = append(append([]*Path{}, c.Path[:c.sequenceStart]...), c.Path[start:end]...)
+		sub.sequence = nil
+		out = append(out, &sub)
+	}
+	return out
+}
+
+// selectedCommands returns the selected commands of the sequences that path is part of, which
+// are all of them for the path before the first command.
+func (c *Context) selectedCommands(path *Path) []*Node {
+	commands := []*Node{}
+	for _, sequence := range c.Sequence() {
+		selected := sequence.Selected()
+		if selected == nil {
+			continue
+		}
+		for _, p := range sequence.Path {
+			if p == path {
+				commands = append(commands, selected)
+				break
+			}
+		}
+	}
+	return commands
+}
+
+// inSequence returns true if parsing may restart after node, a descendant of the node at which
+// command sequences restart.
+func (c *Context) inSequence(node *Node) bool {
+	if c.sequenceNode == nil {
+		return false
+	}
+	for parent := node.Parent; parent != nil; parent = parent.Parent {
+		if parent == c.sequenceNode {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePositional parses a positional argument of node, stopping a cumulative argument at the
+// separator of a sequence.
+func (c *Context) parsePositional(node *Node, arg *Value) error {
+	if c.sequenceSeparator == "" || !arg.IsCumulative() || !c.inSequence(node) {
+		return arg.parse(c.Kong, c.scan, c.getValue(arg), c.seenKeys(arg))
+	}
+	scan := ScanFromTokens(c.scan.PopWhile(func(token Token) bool {
+		return token.IsValue() && token.String() != c.sequenceSeparator
+	})...)
+	err := arg.parse(c.Kong, scan, c.getValue(arg), c.seenKeys(arg))
+	// Return any tokens that were not consumed.
+	rest := scan.PeekAll()
+	for i := len(rest) - 1; i >= 0; i-- {
+		c.scan.PushToken(rest[i])
+	}
+	return err
+}
+
+// checkSequenceRepeat returns an error if command has already been given in the sequence.
+func (c *Context) checkSequenceRepeat(command *Node) error {
+	if len(c.sequence) == 0 {
+		return nil
+	}
+	for _, path := range c.Path[c.sequenceStart:] {
+		if path.Command == command {
+			return fmt.Errorf("command %q can only be given once", command.Name)
+		}
+	}
+	return nil
+}
+
+// restartSequence ends the command at node and continues parsing the next command.
+func (c *Context) restartSequence(node *Node) error {
+	if node.DefaultCmd != nil {
+		c.Path = append(c.Path, &Path{
+			Parent:    node,
+			Command:   node.DefaultCmd,
+			Flags:     node.DefaultCmd.Flags,
+			remainder: c.scan.PeekAll(),
+		})
+	}
+	// A trailing separator.
+	if c.scan.Peek().IsEOL() {
+		return nil
+	}
+	c.sequence = append(c.sequence, len(c.Path))
+	return c.trace(c.sequenceNode)
+}
@@ -0,0 +1,275 @@
+package kong
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// structMapper decodes a struct from "key=value" pairs separated by the value's "sep" tag, eg.
+// --db host=x,port=5432,tls=true. A bool field may be given as just its key to set it to true.
+// It is registered under the name "struct", so must be enabled with a type:"struct" tag.
+//
+// Each exported field is decoded with its own mapper and may use the "name", "help", "type",
+// "format", "required", "default", "enum" and "enumfold" tags. Configuration resolvers may
+// provide an object instead.
+type structMapper struct {
+	r     *Registry
+	cache map[reflect.Type]structMapperFields
+}
+
+func newStructMapper(r *Registry) structMapper {
+	return structMapper{r: r, cache: map[reflect.Type]structMapperFields{}}
+}
+
+// The fields of a struct type, cached by structMapper.
+type structMapperFields struct {
+	fields []structMapperField
+	err    error
+}
+
+// A field of a struct decoded by structMapper.
+type structMapperField struct {
+	name  string
+	index []int
+	tag   *Tag
+}
+
+// fields returns the exported fields of a struct type, or of the struct a pointer refers to.
+func (s structMapper) fields(typ reflect.Type) ([]structMapperField, error) {
+	typ = derefType(typ)
+	if cached, ok := s.cache[typ]; ok {
+		return cached.fields, cached.err
+	}
+	fields, err := s.parseFields(typ)
+	s.cache[typ] = structMapperFields{fields: fields, err: err}
+	return fields, err
+}
+
+func (s structMapper) parseFields(typ reflect.Type) ([]structMapperField, error) {
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a struct but got %s", typ)
+	}
+	v := reflect.New(typ).Elem()
+	fields := []structMapperField{}
+	seen := map[string]bool{}
+	for i := 0; i < typ.NumField(); i++ {
+		ft := typ.Field(i)
+		if !ft.IsExported() {
+			continue
+		}
+		tag, err := parseTag(v, ft)
+		if err != nil {
+			return nil, err
+		}
+		if tag.Ignored {
+			continue
+		}
+		if tag.Cmd || tag.Arg {
+			return nil, failField(v, ft, "commands and arguments are not supported in struct values")
+		}
+		name := tag.Name
+		if name == "" {
+			name = strings.ToLower(dashedString(ft.Name))
+		}
+		if seen[name] {
+			return nil, failField(v, ft, "duplicate field %q", name)
+		}
+		seen[name] = true
+		if s.r.ForNamedValue(tag.Type, v.Field(i)) == nil {
+			return nil, failField(v, ft, "unsupported field type %s", ft.Type)
+		}
+		fields = append(fields, structMapperField{name: name, index: ft.Index, tag: tag})
+	}
+	return fields, nil
+}
+
+func (s structMapper) Decode(ctx *DecodeContext, target reflect.Value) error {
+	if target.Kind() == reflect.Ptr {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		target = target.Elem()
+	}
+	fields, err := s.fields(target.Type())
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("cannot find mapper for %v", target.Type())
+	}
+	t, err := ctx.Scan.PopValue("key=value")
+	if err != nil {
+		return err
+	}
+	entries := map[string]any{}
+	order := []string{}
+	switch v := t.Value.(type) {
+	case string:
+		for _, entry := range SplitEscaped(v, ctx.Value.Tag.Sep) {
+			if entry == "" {
+				continue
+			}
+			key, value, ok := strings.Cut(entry, "=")
+			if !ok {
+				value = ""
+			}
+			if _, dup := entries[key]; dup {
+				return fmt.Errorf("duplicate field %q", key)
+			}
+			entries[key] = value
+			if !ok {
+				entries[key] = nil
+			}
+			order = append(order, key)
+		}
+
+	case map[string]any:
+		for key, value := range v {
+			entries[key] = value
+			order = append(order, key)
+		}
+		sort.Strings(order)
+
+	default:
+		return fmt.Errorf("expected key=value pairs but got %q (%T)", t.Value, t.Value)
+	}
+
+	byName := map[string]structMapperField{}
+	names := []string{}
+	for _, field := range fields {
+		byName[field.name] = field
+		names = append(names, field.name)
+	}
+	for _, key := range order {
+		if _, ok := byName[key]; !ok {
+			return findPotentialCandidates(ctx.suggestOptions(), key, names, "unknown field %q", key)
+		}
+	}
+
+	out := reflect.New(target.Type()).Elem()
+	for _, field := range fields {
+		fv := out.FieldByIndex(field.index)
+		value, ok := entries[field.name]
+		switch {
+		case ok && value == nil:
+			if fv.Kind() != reflect.Bool {
+				return fmt.Errorf("expected %s=<value>", field.name)
+			}
+			fv.SetBool(true)
+			continue
+		case ok:
+		case field.tag.HasDefault:
+			value = field.tag.Default
+		case field.tag.Required:
+			return fmt.Errorf("missing required field %q", field.name)
+		default:
+			continue
+		}
+		if err := s.decodeField(ctx, field, fv, value); err != nil {
+			return fmt.Errorf("%s: %w", field.name, err)
+		}
+	}
+	target.Set(out)
+	return nil
+}
+
+func (s structMapper) decodeField(ctx *DecodeContext, field structMapperField, fv reflect.Value, value any) error {
+	mapper := s.r.ForNamedValue(field.tag.Type, fv)
+	sub := &Value{
+		Name:       field.name,
+		Help:       field.tag.Help,
+		OrigHelp:   field.tag.Help,
+		HasDefault: field.tag.HasDefault,
+		Default:    field.tag.Default,
+		Enum:       field.tag.Enum,
+		Mapper:     mapper,
+		Tag:        field.tag,
+		Target:     fv,
+		Required:   field.tag.Required,
+		Format:     field.tag.Format,
+		Active:     ctx.Value.Active,
+	}
+	if fv.Kind() == reflect.Ptr && fv.IsNil() {
+		fv.Set(reflect.New(fv.Type().Elem()))
+	}
+	if err := mapper.Decode(&DecodeContext{Value: sub, Scan: ScanFromTokens(Token{Type: FlagValueToken, Value: value}), kong: ctx.kong}, fv); err != nil {
+		return err
+	}
+	if sub.Enum == "" {
+		return nil
+	}
+	actual := fmt.Sprintf("%v", reflect.Indirect(fv))
+	for _, enum := range sub.EnumSlice() {
+		if enum == actual {
+			return nil
+		}
+		if field.tag.EnumFold && strings.EqualFold(enum, actual) {
+			if fv.Kind() == reflect.String {
+				fv.SetString(enum)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of %s but got %q", strings.Join(sub.EnumSlice(), ","), actual)
+}
+
+// PlaceHolder shows the schema of the struct, with optional fields in brackets, eg.
+// "host=HOST,[port=5432],[tls]".
+func (s structMapper) PlaceHolder(flag *Flag) string {
+	if flag.PlaceHolder != "" {
+		return flag.PlaceHolder
+	}
+	fields, err := s.fields(flag.Target.Type())
+	if err != nil {
+		return strings.ToUpper(flag.Name)
+	}
+	sep := ","
+	if flag.Tag.Sep != -1 {
+		sep = string(flag.Tag.Sep)
+	}
+	parts := []string{}
+	for _, field := range fields {
+		part := field.name
+		switch {
+		case field.tag.HasDefault:
+			part += "=" + field.tag.Default
+		case field.tag.Enum != "":
+			part += "=" + strings.Join(strings.Split(field.tag.Enum, ","), "|")
+		case derefType(flag.Target.Type()).FieldByIndex(field.index).Type.Kind() != reflect.Bool:
+			part += "=" + strings.ToUpper(strings.ReplaceAll(field.name, "-", "_"))
+		}
+		if !field.tag.Required {
+			part = "[" + part + "]"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, sep)
+}
+
+// fieldHelp describes the fields of the struct that have help, for inclusion in the help of the
+// flag. Returns "" if there are none.
+func (s structMapper) fieldHelp(value *Value) string {
+	fields, err := s.fields(value.Target.Type())
+	if err != nil {
+		return ""
+	}
+	parts := []string{}
+	for _, field := range fields {
+		if field.tag.Help != "" {
+			parts = append(parts, field.name+": "+strings.TrimSuffix(field.tag.Help, "."))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "Fields: " + strings.Join(parts, "; ") + "."
+}
+
+func derefType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
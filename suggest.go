@@ -0,0 +1,96 @@
+package kong
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// SuggestOptions configures the "did you mean" suggestions Kong includes in errors for unknown
+// flags, commands, enum values and configuration keys.
+//
+// SuggestOptions{} disables suggestions.
+type SuggestOptions struct {
+	// MaxDistance is the maximum Damerau-Levenshtein distance between the input and a candidate
+	// for the candidate to be suggested. Zero disables distance matching.
+	//
+	// Regardless of MaxDistance, a candidate more than one edit away must be closer than half the
+	// length of the longer of the input and the candidate, ignoring leading dashes, so that
+	// unrelated short names are not suggested.
+	MaxDistance int
+
+	// Prefix suggests candidates that start with the input.
+	Prefix bool
+
+	// Limit is the maximum number of suggestions. Zero means no limit.
+	Limit int
+}
+
+// DefaultSuggestOptions are the SuggestOptions used unless a SuggestOptions is passed to New.
+var DefaultSuggestOptions = SuggestOptions{MaxDistance: 2, Prefix: true}
+
+// Apply options to Kong as a configuration option.
+func (s SuggestOptions) Apply(k *Kong) error {
+	k.suggestOptions = s
+	return nil
+}
+
+// Suggest returns the candidates that are close to input, closest first.
+func (s SuggestOptions) Suggest(input string, candidates []string) []string {
+	type suggestion struct {
+		candidate string
+		distance  int
+	}
+	suggestions := []suggestion{}
+	seen := map[string]bool{}
+	for _, candidate := range candidates {
+		if candidate == input || seen[candidate] {
+			continue
+		}
+		distance := damerauLevenshtein(input, candidate)
+		prefix := s.Prefix && input != "" && strings.HasPrefix(candidate, input)
+		// The dashes of flags don't make their names any more similar.
+		length := utf8.RuneCountInString(strings.TrimLeft(input, "-"))
+		if n := utf8.RuneCountInString(strings.TrimLeft(candidate, "-")); n > length {
+			length = n
+		}
+		near := s.MaxDistance > 0 && distance <= s.MaxDistance && distance < length && (distance == 1 || distance < length/2)
+		if prefix || near {
+			seen[candidate] = true
+			suggestions = append(suggestions, suggestion{candidate, distance})
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].distance < suggestions[j].distance
+	})
+	if s.Limit > 0 && len(suggestions) > s.Limit {
+		suggestions = suggestions[:s.Limit]
+	}
+	out := make([]string, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		out = append(out, suggestion.candidate)
+	}
+	return out
+}
+
+// didYouMean returns a ", did you mean ...?" suffix for an error about input, or "" if there
+// are no suggestions.
+func (s SuggestOptions) didYouMean(input string, candidates []string) string {
+	suggestions := s.Suggest(input, candidates)
+	for i, suggestion := range suggestions {
+		suggestions[i] = fmt.Sprintf("%q", suggestion)
+	}
+	switch len(suggestions) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf(", did you mean %s?", suggestions[0])
+	default:
+		return fmt.Sprintf(", did you mean one of %s?", strings.Join(suggestions, ", "))
+	}
+}
+
+func findPotentialCandidates(suggest SuggestOptions, needle string, haystack []string, format string, args ...any) error {
+	return fmt.Errorf("%s%s", fmt.Sprintf(format, args...), suggest.didYouMean(needle, haystack))
+}
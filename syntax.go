@@ -0,0 +1,116 @@
+package kong
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// FlagSyntax controls how command-line arguments are recognised as flags.
+//
+// Regardless of syntax, help and error messages display flags in GNU syntax.
+type FlagSyntax int
+
+const (
+	// GNUFlagSyntax recognises long flags as --name[=value] and short flags as -n[value]. It is the default.
+	GNUFlagSyntax FlagSyntax = iota
+	// GoFlagSyntax additionally recognises long flags with a single dash, as in the Go flag package,
+	// eg. -name[=value]. Single character flags such as -n are matched as short flags, and arguments
+	// that do not name a known flag are parsed as GNU short flags, so -ofile and clusters such as
+	// -vd keep working.
+	GoFlagSyntax
+	// WindowsFlagSyntax additionally recognises flags prefixed with a slash, eg. /name[:value] or
+	// /n[:value]. Arguments that do not name a known flag, such as /tmp, are treated as positional
+	// arguments.
+	WindowsFlagSyntax
+)
+
+// WithFlagSyntax configures how command-line arguments are recognised as flags.
+func WithFlagSyntax(syntax FlagSyntax) Option {
+	return OptionFunc(func(k *Kong) error {
+		k.flagSyntax = syntax
+		return nil
+	})
+}
+
+// WithShortFlagClustering enables or disables combining short flags, eg. -abc for -a -b -c.
+//
+// Enabled by default. When disabled, the remainder of a short flag is only accepted as its value,
+// eg. -ofile.
+func WithShortFlagClustering(enable bool) Option {
+	return OptionFunc(func(k *Kong) error {
+		k.noShortFlagClustering = !enable
+		return nil
+	})
+}
+
+// split an argument into flag tokens, if it is a flag in this syntax that is not also a GNU flag.
+// The caller must check that the flag exists.
+//
+// Tokens are returned in order. Returns false if the argument is not handled by this syntax.
+func (f FlagSyntax) split(arg string) ([]Token, bool) {
+	var (
+		body   string
+		assign string
+	)
+	switch f {
+	case GoFlagSyntax:
+		if !strings.HasPrefix(arg, "-") || strings.HasPrefix(arg, "--") || utf8.RuneCountInString(arg) < 3 {
+			return nil, false
+		}
+		body, assign = arg[1:], "="
+
+	case WindowsFlagSyntax:
+		if !strings.HasPrefix(arg, "/") || len(arg) < 2 {
+			return nil, false
+		}
+		body, assign = arg[1:], ":="
+
+	default:
+		return nil, false
+	}
+	name, value, hasValue := body, "", false
+	if i := strings.IndexAny(body, assign); i >= 0 {
+		name, value, hasValue = body[:i], body[i+1:], true
+	}
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, false
+	}
+	tokens := []Token{{Type: FlagToken, Value: name}}
+	if utf8.RuneCountInString(name) == 1 {
+		tokens[0].Type = ShortFlagToken
+	}
+	if hasValue {
+		tokens = append(tokens, Token{Type: FlagValueToken, Value: value})
+	}
+	return tokens, true
+}
+
+// isFlag returns true if match, eg. "--name" or "-n", is the name of one of flags, or an
+// abbreviation of one if abbreviations are allowed.
+func (c *Context) isFlag(flags []*Flag, match string) bool {
+	if c.allowAbbreviations {
+		expanded, err := expandFlagAbbreviation(flags, match, c.caseInsensitive)
+		if err != nil {
+			// Ambiguous, which is reported when the flag is parsed.
+			return true
+		}
+		match = expanded
+	}
+	for _, flag := range flags {
+		if flagMatches(flag, match) {
+			return true
+		}
+		if !c.caseInsensitive || !strings.HasPrefix(match, "--") {
+			continue
+		}
+		for _, name := range append([]string{flag.Name}, flag.Aliases...) {
+			if c.equalNames("--"+name, match) {
+				return true
+			}
+		}
+		if neg := negatableFlagName(flag.Name, flag.Tag.Negatable); neg != "" && c.equalNames(neg, match) {
+			return true
+		}
+	}
+	return false
+}
@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 )
 
@@ -35,6 +37,8 @@ type Tag struct {
 	HasDefault      bool
 	Default         string
 	Format          string
+	FromFile        bool   // Read "@path" and "-" values from a file or stdin.
+	TZ              string // Location for times without a zone, eg. tz:"Local".
 	PlaceHolder     string
 	Envs            []string
 	Short           rune
@@ -42,6 +46,16 @@ type Tag struct {
 	Sep             rune
 	MapSep          rune
 	Enum            string
+	EnumFold        bool              // Match enum values case-insensitively.
+	Unique          bool              // Remove duplicate slice elements.
+	Sorted          bool              // Sort slice elements, in enum order if there is an enum.
+	MinItems        int               // Minimum number of slice elements, if non-zero.
+	MaxItems        int               // Maximum number of slice elements, if non-zero.
+	KeyEnum         string            // Set of valid map keys.
+	KeyFormat       string            // Regular expression valid map keys must match.
+	RequiredKeys    []string          // Map keys that must be present.
+	NoDupes         bool              // Error on repeated map keys rather than overwriting.
+	ValueTypes      map[string]string // Named mappers for the values of specific map keys.
 	Group           string
 	Xor             []string
 	And             []string
@@ -51,10 +65,16 @@ type Tag struct {
 	XorPrefix       string // Optional prefix on XOR/AND groups.
 	Embed           bool
 	Aliases         []string
+	Inherit         []string // Names of the flags of ancestors inherited by a command, if not nil.
 	Negatable       string
+	Global          bool // Recognised by every command.
+	GlobalAll       bool // Also recognised within passthrough arguments, up to "--".
+	NoPropagate     bool // Flag is not inherited by sub-commands.
 	Passthrough     bool // Deprecated: use PassthroughMode instead.
 	PassthroughMode PassthroughMode
 
+	keyFormat *regexp.Regexp // Compiled KeyFormat, anchored to match whole keys.
+
 	// Storage for all tag keys for arbitrary lookups.
 	items map[string][]string
 }
@@ -286,6 +306,13 @@ func hydrateTag(t *Tag, typ reflect.Type) error { //nolint: gocyclo
 	}
 	t.Hidden = t.Has("hidden")
 	t.Format = t.Get("format")
+	t.FromFile = t.Has("fromfile")
+	t.TZ = t.Get("tz")
+	if t.TZ != "" {
+		if _, err := time.LoadLocation(t.TZ); err != nil {
+			return fmt.Errorf("invalid tz %q: %w", t.TZ, err)
+		}
+	}
 	t.Sep, _ = t.GetSep("sep", ',')
 	t.MapSep, _ = t.GetSep("mapsep", ';')
 	t.Group = t.Get("group")
@@ -323,10 +350,57 @@ func hydrateTag(t *Tag, typ reflect.Type) error { //nolint: gocyclo
 	}
 	t.PlaceHolder = t.Get("placeholder")
 	t.Enum = t.Get("enum")
+	t.EnumFold = t.Has("enumfold")
+	t.Unique = t.Has("unique")
+	t.Sorted = t.Has("sorted")
+	if t.MinItems, err = t.getItems("minitems"); err != nil {
+		return err
+	}
+	if t.MaxItems, err = t.getItems("maxitems"); err != nil {
+		return err
+	}
+	if t.Unique || t.Sorted || t.MinItems != 0 || t.MaxItems != 0 {
+		if typ != nil && derefType(typ).Kind() != reflect.Slice {
+			return fmt.Errorf("unique, sorted, minitems and maxitems can only be applied to slices")
+		}
+		if t.MaxItems != 0 && t.MinItems > t.MaxItems {
+			return fmt.Errorf("minitems %d is greater than maxitems %d", t.MinItems, t.MaxItems)
+		}
+	}
 	scalarType := typ == nil || !(typ.Kind() == reflect.Slice || typ.Kind() == reflect.Map || typ.Kind() == reflect.Ptr)
 	if t.Enum != "" && !(t.Required || t.HasDefault) && scalarType {
 		return fmt.Errorf("enum value is only valid if it is either required or has a valid default value")
 	}
+	if err := t.hydrateMapTags(typ); err != nil {
+		return err
+	}
+	t.Global = t.Has("global")
+	if t.Global && (t.Arg || t.Cmd) {
+		return fmt.Errorf("global only makes sense for flags")
+	}
+	switch global := t.Get("global"); global {
+	case "":
+	case "all":
+		t.GlobalAll = true
+	default:
+		return fmt.Errorf("invalid global mode %q, must be 'all' if given", global)
+	}
+	t.NoPropagate = t.Has("nopropagate")
+	if t.NoPropagate && (t.Arg || t.Cmd) {
+		return fmt.Errorf("nopropagate only makes sense for flags")
+	}
+	if t.NoPropagate && t.Global {
+		return fmt.Errorf("global flags can not be nopropagate")
+	}
+	if t.Has("inherit") {
+		if !t.Cmd {
+			return fmt.Errorf("inherit only makes sense for commands")
+		}
+		t.Inherit = []string{}
+		if inherit := t.Get("inherit"); inherit != "-" {
+			t.Inherit = append(t.Inherit, strings.FieldsFunc(inherit, tagSplitFn)...)
+		}
+	}
 	passthrough := t.Has("passthrough")
 	if passthrough && !t.Arg && !t.Cmd {
 		return fmt.Errorf("passthrough only makes sense for positional arguments or commands")
@@ -393,6 +467,73 @@ func (t *Tag) GetRune(k string) (rune, error) {
 	return r, nil
 }
 
+func (t *Tag) hydrateMapTags(typ reflect.Type) error {
+	t.KeyEnum = t.Get("keyenum")
+	t.KeyFormat = t.Get("keyformat")
+	for _, keys := range t.GetAll("requiredkeys") {
+		t.RequiredKeys = append(t.RequiredKeys, strings.FieldsFunc(keys, tagSplitFn)...)
+	}
+	t.NoDupes = t.Has("nodupes")
+	for _, types := range t.GetAll("valuetypes") {
+		for _, entry := range strings.FieldsFunc(types, tagSplitFn) {
+			key, name, ok := strings.Cut(entry, "=")
+			if !ok || key == "" || name == "" {
+				return fmt.Errorf("valuetypes should be in the form key=type but got %q", entry)
+			}
+			if t.ValueTypes == nil {
+				t.ValueTypes = map[string]string{}
+			}
+			t.ValueTypes[key] = name
+		}
+	}
+	if t.KeyEnum == "" && t.KeyFormat == "" && len(t.RequiredKeys) == 0 && !t.NoDupes && len(t.ValueTypes) == 0 {
+		return nil
+	}
+	if typ != nil && derefType(typ).Kind() != reflect.Map {
+		return fmt.Errorf("keyenum, keyformat, requiredkeys, nodupes and valuetypes can only be applied to maps")
+	}
+	if err := t.compileKeyFormat(); err != nil {
+		return err
+	}
+	if t.KeyEnum != "" {
+		keys := map[string]bool{}
+		for _, key := range strings.Split(t.KeyEnum, ",") {
+			keys[strings.TrimSpace(key)] = true
+		}
+		for _, key := range t.RequiredKeys {
+			if !keys[key] {
+				return fmt.Errorf("required key %q is not in keyenum", key)
+			}
+		}
+	}
+	return nil
+}
+
+// compileKeyFormat compiles the KeyFormat of t, if any.
+func (t *Tag) compileKeyFormat() error {
+	if t.KeyFormat == "" {
+		return nil
+	}
+	re, err := regexp.Compile("^(?:" + t.KeyFormat + ")$")
+	if err != nil {
+		return fmt.Errorf("invalid keyformat %q: %w", t.KeyFormat, err)
+	}
+	t.keyFormat = re
+	return nil
+}
+
+// getItems parses the given tag as a non-negative number of slice elements, or 0 if absent.
+func (t *Tag) getItems(k string) (int, error) {
+	if !t.Has(k) {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(t.Get(k))
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s %q, must be a non-negative integer", k, t.Get(k))
+	}
+	return n, nil
+}
+
 // GetSep parses the given tag as a rune separator, allowing for a default or none.
 // The separator is returned, or -1 if "none" is specified. If the tag value is an
 // invalid utf8 sequence, the default rune is returned as well as an error. If the